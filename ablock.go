@@ -5,10 +5,17 @@
 package factom
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
+
+	"github.com/FactomProject/btcutil/base58"
 )
 
 var (
@@ -80,6 +87,7 @@ func (id AdminID) String() string {
 type ABlock struct {
 	PrevBackreferenceHash string    `json:"prevbackrefhash"`
 	DBHeight              int64     `json:"dbheight"`
+	HeaderExpansionArea   string    `json:"headerexpansionarea,omitempty"`
 	BackReferenceHash     string    `json:"backreferencehash"`
 	LookupHash            string    `json:"lookuphash"`
 	ABEntries             []ABEntry `json:"abentries"`
@@ -107,6 +115,7 @@ func (a *ABlock) UnmarshalJSON(js []byte) error {
 		Header struct {
 			PrevBackreferenceHash string `json:"prevbackrefhash"`
 			DBHeight              int64  `json:"dbheight"`
+			HeaderExpansionArea   string `json:"headerexpansionarea"`
 		}
 		BackReferenceHash string            `json:"backreferencehash"`
 		LookupHash        string            `json:"lookuphash"`
@@ -120,6 +129,7 @@ func (a *ABlock) UnmarshalJSON(js []byte) error {
 
 	a.PrevBackreferenceHash = tmp.Header.PrevBackreferenceHash
 	a.DBHeight = tmp.Header.DBHeight
+	a.HeaderExpansionArea = tmp.Header.HeaderExpansionArea
 	a.BackReferenceHash = tmp.BackReferenceHash
 	a.LookupHash = tmp.LookupHash
 
@@ -240,6 +250,428 @@ func (a *ABlock) UnmarshalJSON(js []byte) error {
 	return nil
 }
 
+// adminChainID is the ChainID of the Admin Blocks.
+const adminChainID = "000000000000000000000000000000000000000000000000000000000000000a"
+
+// UnmarshalBinary decodes a binary Admin Block, such as the raw data returned
+// by GetRaw, into the same ABEntry types used for the JSON form. The
+// BackReferenceHash and LookupHash are computed from the decoded block.
+func (a *ABlock) UnmarshalBinary(data []byte) error {
+	_, err := a.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData decodes a binary Admin Block and returns the remainder
+// of the byte stream.
+func (a *ABlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	r := newBlockReader(data)
+
+	n := new(ABlock)
+	if id := r.hash("AdminChainID"); r.err == nil && id != adminChainID {
+		return nil, fmt.Errorf("%w: AdminChainID is %s", ErrInvalidBlock, id)
+	}
+	n.PrevBackreferenceHash = r.hash("PrevBackreferenceHash")
+	n.DBHeight = int64(r.uint32("DBHeight"))
+	n.HeaderExpansionArea = hex.EncodeToString(r.next(int(r.varint("HeaderExpansionSize")), "HeaderExpansionArea"))
+	count := int(r.uint32("MessageCount"))
+	size := int(r.uint32("BodySize"))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	body := r.next(size, "Body")
+	if r.err != nil {
+		return nil, r.err
+	}
+	br := newBlockReader(body)
+	for i := 0; i < count; i++ {
+		e, err := unmarshalABEntry(br, i)
+		if err != nil {
+			return nil, fmt.Errorf("ABEntries[%d]: %w", i, err)
+		}
+		n.ABEntries = append(n.ABEntries, e)
+	}
+	if l := len(br.remaining()); l > 0 {
+		return nil, fmt.Errorf("%w: %d bytes of the body follow the last of %d ABEntries",
+			ErrInvalidBlock, l, count)
+	}
+
+	n.LookupHash = hex.EncodeToString(sha(data[:r.off]))
+	n.BackReferenceHash = hex.EncodeToString(sha512Half(data[:r.off]))
+
+	*a = *n
+	return r.remaining(), nil
+}
+
+// unmarshalABEntry reads an ABEntry from the body of an Admin Block.
+func unmarshalABEntry(r *blockReader, i int) (ABEntry, error) {
+	id := AdminID(r.uint8("AdminIDType"))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// the newer types are prefixed with the size of their data
+	if id >= AIDCoinbaseDescriptor {
+		size := r.varint(id.String() + " size")
+		p := r.next(int(size), id.String())
+		if r.err != nil {
+			return nil, r.err
+		}
+		sub := newBlockReader(p)
+		e, err := unmarshalABEntryData(sub, id)
+		if err != nil {
+			return nil, err
+		}
+		if l := len(sub.remaining()); l > 0 {
+			return nil, fmt.Errorf("%w: %s has %d bytes left over", ErrInvalidBlock, id, l)
+		}
+		return e, nil
+	}
+
+	return unmarshalABEntryData(r, id)
+}
+
+func unmarshalABEntryData(r *blockReader, id AdminID) (ABEntry, error) {
+	var e ABEntry
+	switch id {
+	case AIDMinuteNumber:
+		e = &AdminMinuteNumber{
+			MinuteNumber: int(r.uint8("MinuteNumber")),
+		}
+	case AIDDBSignature:
+		v := new(AdminDBSignature)
+		v.IdentityChainID = r.hash("IdentityChainID")
+		v.PreviousSignature.Pub = r.hash("Pub")
+		v.PreviousSignature.Sig = r.hex(64, "Sig")
+		e = v
+	case AIDRevealHash:
+		e = &AdminRevealHash{
+			IdentityChainID: r.hash("IdentityChainID"),
+			MatryoshkaHash:  r.hash("MatryoshkaHash"),
+		}
+	case AIDAddHash:
+		e = &AdminAddHash{
+			IdentityChainID: r.hash("IdentityChainID"),
+			MatryoshkaHash:  r.hash("MatryoshkaHash"),
+		}
+	case AIDIncreaseServerCount:
+		e = &AdminIncreaseServerCount{
+			Amount: int(r.uint8("Amount")),
+		}
+	case AIDAddFederatedServer:
+		e = &AdminAddFederatedServer{
+			IdentityChainID: r.hash("IdentityChainID"),
+			DBHeight:        int64(r.uint32("DBHeight")),
+		}
+	case AIDAddAuditServer:
+		e = &AdminAddAuditServer{
+			IdentityChainID: r.hash("IdentityChainID"),
+			DBHeight:        int64(r.uint32("DBHeight")),
+		}
+	case AIDRemoveFederatedServer:
+		e = &AdminRemoveFederatedServer{
+			IdentityChainID: r.hash("IdentityChainID"),
+			DBHeight:        int64(r.uint32("DBHeight")),
+		}
+	case AIDAddFederatedServerKey:
+		e = &AdminAddFederatedServerKey{
+			IdentityChainID: r.hash("IdentityChainID"),
+			KeyPriority:     int(r.uint8("KeyPriority")),
+			PublicKey:       r.hash("PublicKey"),
+			DBHeight:        int(r.uint32("DBHeight")),
+		}
+	case AIDAddFederatedServerBTCKey:
+		e = &AdminAddFederatedServerBTCKey{
+			IdentityChainID: r.hash("IdentityChainID"),
+			KeyPriority:     int(r.uint8("KeyPriority")),
+			KeyType:         int(r.uint8("KeyType")),
+			ECDSAPublicKey:  btcKeyAddress(r.next(20, "ECDSAPublicKey")),
+		}
+	case AIDServerFault:
+		v := new(AdminServerFault)
+		v.Timestamp = strconv.FormatUint(uint48(r.next(6, "Timestamp")), 10)
+		v.ServerID = r.hash("ServerID")
+		v.AuditServerID = r.hash("AuditServerID")
+		v.VMIndex = int(r.uint8("VMIndex"))
+		v.DBHeight = int(r.uint32("DBHeight"))
+		v.Height = int(r.uint32("Height"))
+		sigs := serverFaultSignatures{Length: r.uint32("SignatureList")}
+		for j := 0; j < int(sigs.Length) && r.err == nil; j++ {
+			var sig serverFaultSignature
+			sig.Pub = r.hash("Pub")
+			sig.Sig = r.hex(64, "Sig")
+			sigs.List = append(sigs.List, sig)
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		p, err := json.Marshal(sigs)
+		if err != nil {
+			return nil, err
+		}
+		v.SignatureList = p
+		e = v
+	case AIDCoinbaseDescriptor:
+		v := new(AdminCoinbaseDescriptor)
+		for len(r.remaining()) > 0 && r.err == nil {
+			amount := r.varint("Amount")
+			rcdHash := r.hash("Address")
+			v.Outputs = append(v.Outputs, struct {
+				Amount  int    `json:"amount"`
+				Address string `json:"address"`
+			}{int(amount), rcdHash})
+		}
+		e = v
+	case AIDCoinbaseDescriptorCancel:
+		e = &AdminCoinbaseDescriptorCancel{
+			DescriptorHeight: int(r.varint("DescriptorHeight")),
+			DescriptorIndex:  int(r.varint("DescriptorIndex")),
+		}
+	case AIDAddAuthorityAddress:
+		e = &AdminAddAuthorityAddress{
+			IdentityChainID: r.hash("IdentityChainID"),
+			FactoidAddress:  FactoidAddressFromRCDHash(r.next(32, "FactoidAddress")),
+		}
+	case AIDAddAuthorityEfficiency:
+		e = &AdminAddAuthorityEfficiency{
+			IdentityChainID: r.hash("IdentityChainID"),
+			Efficiency:      int(r.uint16("Efficiency")),
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrAIDUnknown, id)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return e, nil
+}
+
+// serverFaultSignatures is the JSON form of the SignatureList of an
+// AdminServerFault.
+type serverFaultSignatures struct {
+	Length uint32                 `json:"Length"`
+	List   []serverFaultSignature `json:"List"`
+}
+
+type serverFaultSignature struct {
+	Pub string `json:"pub"`
+	Sig string `json:"sig"`
+}
+
+// MarshalBinary encodes the Admin Block in the binary form used by factomd.
+func (a *ABlock) MarshalBinary() ([]byte, error) {
+	body := new(bytes.Buffer)
+	for i, e := range a.ABEntries {
+		if err := marshalABEntry(body, e); err != nil {
+			return nil, fmt.Errorf("ABEntries[%d]: %w", i, err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+
+	// 32 byte AdminChainID and PrevBackreferenceHash
+	writeHash(buf, adminChainID, "AdminChainID")
+	if err := writeHash(buf, a.PrevBackreferenceHash, "PrevBackreferenceHash"); err != nil {
+		return nil, err
+	}
+
+	// 4 byte DBHeight
+	binary.Write(buf, binary.BigEndian, uint32(a.DBHeight))
+
+	// varint HeaderExpansionSize and HeaderExpansionArea
+	x, err := hex.DecodeString(a.HeaderExpansionArea)
+	if err != nil {
+		return nil, fmt.Errorf("HeaderExpansionArea: %w", err)
+	}
+	writeVarInt(buf, uint64(len(x)))
+	buf.Write(x)
+
+	// 4 byte MessageCount and BodySize
+	binary.Write(buf, binary.BigEndian, uint32(len(a.ABEntries)))
+	binary.Write(buf, binary.BigEndian, uint32(body.Len()))
+
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// marshalABEntry writes the AdminIDType and data of an ABEntry.
+func marshalABEntry(buf *bytes.Buffer, e ABEntry) error {
+	buf.WriteByte(byte(e.Type()))
+
+	// the newer types are prefixed with the size of their data
+	if e.Type() >= AIDCoinbaseDescriptor {
+		data := new(bytes.Buffer)
+		if err := marshalABEntryData(data, e); err != nil {
+			return err
+		}
+		writeVarInt(buf, uint64(data.Len()))
+		buf.Write(data.Bytes())
+		return nil
+	}
+
+	return marshalABEntryData(buf, e)
+}
+
+func marshalABEntryData(buf *bytes.Buffer, e ABEntry) error {
+	switch v := e.(type) {
+	case *AdminMinuteNumber:
+		buf.WriteByte(byte(v.MinuteNumber))
+	case *AdminDBSignature:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		if err := writeHash(buf, v.PreviousSignature.Pub, "Pub"); err != nil {
+			return err
+		}
+		return writeHex(buf, v.PreviousSignature.Sig, 64, "Sig")
+	case *AdminRevealHash:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		return writeHash(buf, v.MatryoshkaHash, "MatryoshkaHash")
+	case *AdminAddHash:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		return writeHash(buf, v.MatryoshkaHash, "MatryoshkaHash")
+	case *AdminIncreaseServerCount:
+		buf.WriteByte(byte(v.Amount))
+	case *AdminAddFederatedServer:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint32(v.DBHeight))
+	case *AdminAddAuditServer:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint32(v.DBHeight))
+	case *AdminRemoveFederatedServer:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint32(v.DBHeight))
+	case *AdminAddFederatedServerKey:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		buf.WriteByte(byte(v.KeyPriority))
+		if err := writeHash(buf, v.PublicKey, "PublicKey"); err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint32(v.DBHeight))
+	case *AdminAddFederatedServerBTCKey:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		buf.WriteByte(byte(v.KeyPriority))
+		buf.WriteByte(byte(v.KeyType))
+		p, err := btcKeyHash(v.ECDSAPublicKey)
+		if err != nil {
+			return fmt.Errorf("ECDSAPublicKey %s: %w", v.ECDSAPublicKey, err)
+		}
+		buf.Write(p)
+	case *AdminServerFault:
+		t, err := strconv.ParseUint(v.Timestamp, 10, 48)
+		if err != nil {
+			return fmt.Errorf("Timestamp: %w", err)
+		}
+		buf.Write(putUint48(t))
+		if err := writeHash(buf, v.ServerID, "ServerID"); err != nil {
+			return err
+		}
+		if err := writeHash(buf, v.AuditServerID, "AuditServerID"); err != nil {
+			return err
+		}
+		buf.WriteByte(byte(v.VMIndex))
+		binary.Write(buf, binary.BigEndian, uint32(v.DBHeight))
+		binary.Write(buf, binary.BigEndian, uint32(v.Height))
+		sigs := new(serverFaultSignatures)
+		if len(v.SignatureList) > 0 {
+			if err := json.Unmarshal(v.SignatureList, sigs); err != nil {
+				return fmt.Errorf("SignatureList: %w", err)
+			}
+		}
+		binary.Write(buf, binary.BigEndian, uint32(len(sigs.List)))
+		for _, sig := range sigs.List {
+			if err := writeHash(buf, sig.Pub, "Pub"); err != nil {
+				return err
+			}
+			if err := writeHex(buf, sig.Sig, 64, "Sig"); err != nil {
+				return err
+			}
+		}
+	case *AdminCoinbaseDescriptor:
+		for _, o := range v.Outputs {
+			writeVarInt(buf, uint64(o.Amount))
+			if err := writeHash(buf, o.Address, "Address"); err != nil {
+				return err
+			}
+		}
+	case *AdminCoinbaseDescriptorCancel:
+		writeVarInt(buf, uint64(v.DescriptorHeight))
+		writeVarInt(buf, uint64(v.DescriptorIndex))
+	case *AdminAddAuthorityAddress:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		rcdHash, err := RCDHashFromFactoidAddress(v.FactoidAddress)
+		if err != nil {
+			return fmt.Errorf("FactoidAddress %s: %w", v.FactoidAddress, err)
+		}
+		buf.Write(rcdHash)
+	case *AdminAddAuthorityEfficiency:
+		if err := writeHash(buf, v.IdentityChainID, "IdentityChainID"); err != nil {
+			return err
+		}
+		binary.Write(buf, binary.BigEndian, uint16(v.Efficiency))
+	default:
+		return fmt.Errorf("%w: %d", ErrAIDUnknown, e.Type())
+	}
+	return nil
+}
+
+// btcKeyAddress returns the base58check form of the 20 byte Bitcoin key hash
+// of an AdminAddFederatedServerBTCKey, as factomd shows it in the JSON form.
+func btcKeyAddress(p []byte) string {
+	b := append([]byte{0x00}, p...)
+	return base58.Encode(append(b, shad(b)[:ChecksumLength]...))
+}
+
+// btcKeyHash returns the 20 byte Bitcoin key hash of a base58check
+// ECDSAPublicKey.
+func btcKeyHash(s string) ([]byte, error) {
+	p, version, err := base58.CheckDecodeWithOneVersionByte(s)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0x00 || len(p) != 20 {
+		return nil, ErrInvalidAddress
+	}
+	return p, nil
+}
+
+// ComputeLookupHash computes the sha256 hash of the binary Admin Block, which
+// it is referenced by in the Directory Block.
+func (a *ABlock) ComputeLookupHash() ([]byte, error) {
+	p, err := a.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sha(p), nil
+}
+
+// ComputeBackReferenceHash computes the sha512 half hash of the binary Admin
+// Block, which the next Admin Block references as its PrevBackreferenceHash.
+func (a *ABlock) ComputeBackReferenceHash() ([]byte, error) {
+	p, err := a.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sha512Half(p), nil
+}
+
 // ABEntry is any valid Admin Block Entry type
 type ABEntry interface {
 	Type() AdminID
@@ -602,24 +1034,14 @@ func (a *AdminAddAuthorityEfficiency) String() string {
 }
 
 // GetABlock requests a specific ABlock from the factomd API
-func GetABlock(keymr string) (ablock *ABlock, err error) {
+func (c *Client) GetABlock(ctx context.Context, keymr string) (ablock *ABlock, err error) {
 	params := keyMRRequest{KeyMR: keymr, NoRaw: true}
 	req := NewJSON2Request("admin-block", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	// create a wraper construct for the ECBlock API return
 	wrap := new(struct {
 		ABlock *ABlock `json:"ablock"`
 	})
-
-	err = json.Unmarshal(resp.JSONResult(), wrap)
-	if err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
@@ -627,21 +1049,13 @@ func GetABlock(keymr string) (ablock *ABlock, err error) {
 }
 
 // GetABlockByHeight requests an ABlock of a specific height from the factomd
-func GetABlockByHeight(height int64) (ablock *ABlock, err error) {
+func (c *Client) GetABlockByHeight(ctx context.Context, height int64) (ablock *ABlock, err error) {
 	params := heightRequest{Height: height, NoRaw: true}
 	req := NewJSON2Request("ablock-by-height", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	wrap := new(struct {
 		ABlock *ABlock `json:"ablock"`
 	})
-	if err = json.Unmarshal(resp.JSONResult(), wrap); err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
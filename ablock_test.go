@@ -5,10 +5,14 @@
 package factom_test
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 
 	. "github.com/FactomProject/factom"
 
@@ -125,3 +129,166 @@ func TestGetABlockByHeight(t *testing.T) {
 	}
 	t.Log("ABlock:", ab)
 }
+
+func TestABlockUnmarshalBinary(t *testing.T) {
+	js := []byte(`{"header":{"prevbackrefhash":"e3549cd600cbb00d6f8bf4c505ee74f6dc5326d7aa02bb7e4b33f8f16bd6f3f5","dbheight":20000,"headerexpansionsize":0,"headerexpansionarea":"","messagecount":2,"bodysize":131,"adminchainid":"000000000000000000000000000000000000000000000000000000000000000a","chainid":"000000000000000000000000000000000000000000000000000000000000000a"},"abentries":[{"adminidtype":1,"identityadminchainid":"0000000000000000000000000000000000000000000000000000000000000000","prevdbsig":{"pub":"0426a802617848d4d16d87830fc521f4d136bb2d0c352850919c2679f189613a","sig":"a7d55725393d78a0e623141a41bfcb64956d308eeb1ae501243ad171c2ed42e62a654e138025d0439ecb5bbf594315c191fa88eedb699d9b63a426a6036d630d"}},{"adminidtype":0,"minutenumber":1}],"backreferencehash":"c8ad13a2aea0f961bf73ac9e79ae8aa0d77ddf59e7d02931de7b9e53a3a20c5e","lookuphash":"e7eb4bda495dbe7657cae1525b6be78bd2fdbad952ebde506b6a97e1cf8f431e"}`)
+	raw, _ := hex.DecodeString("000000000000000000000000000000000000000000000000000000000000000ae3549cd600cbb00d6f8bf4c505ee74f6dc5326d7aa02bb7e4b33f8f16bd6f3f500004e200000000002000000830100000000000000000000000000000000000000000000000000000000000000000426a802617848d4d16d87830fc521f4d136bb2d0c352850919c2679f189613aa7d55725393d78a0e623141a41bfcb64956d308eeb1ae501243ad171c2ed42e62a654e138025d0439ecb5bbf594315c191fa88eedb699d9b63a426a6036d630d0001")
+
+	expected := new(ABlock)
+	if err := json.Unmarshal(js, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	ab := new(ABlock)
+	if err := ab.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ab, expected) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", expected, ab)
+	}
+
+	if p, err := ab.MarshalBinary(); err != nil || !bytes.Equal(p, raw) {
+		t.Errorf("expected:%x\nrecieved:%x %v", raw, p, err)
+	}
+	if h, err := ab.ComputeLookupHash(); err != nil || hex.EncodeToString(h) != expected.LookupHash {
+		t.Errorf("unexpected LookupHash %x %v", h, err)
+	}
+	if h, err := ab.ComputeBackReferenceHash(); err != nil || hex.EncodeToString(h) != expected.BackReferenceHash {
+		t.Errorf("unexpected BackReferenceHash %x %v", h, err)
+	}
+
+	for _, l := range []int{0, 70, 100, len(raw) - 1} {
+		if err := new(ABlock).UnmarshalBinary(raw[:l]); !errors.Is(err, ErrBlockTruncated) {
+			t.Errorf("%d bytes: expected %v, got %v", l, ErrBlockTruncated, err)
+		}
+	}
+	bad := append([]byte(nil), raw...)
+	bad[77] = 15
+	if err := new(ABlock).UnmarshalBinary(bad); !errors.Is(err, ErrAIDUnknown) {
+		t.Errorf("expected %v, got %v", ErrAIDUnknown, err)
+	}
+}
+
+func TestABlockBinaryRoundTrip(t *testing.T) {
+	hash := func(b byte) string {
+		return hex.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	}
+
+	sig := new(AdminDBSignature)
+	sig.IdentityChainID = hash(0x01)
+	sig.PreviousSignature.Pub = hash(0x02)
+	sig.PreviousSignature.Sig = hash(0x03) + hash(0x04)
+
+	coinbase := new(AdminCoinbaseDescriptor)
+	coinbase.Outputs = append(coinbase.Outputs, struct {
+		Amount  int    `json:"amount"`
+		Address string `json:"address"`
+	}{640000000000, hash(0xbb)})
+
+	ab := &ABlock{
+		PrevBackreferenceHash: hash(0xe3),
+		DBHeight:              200000,
+		HeaderExpansionArea:   "0102",
+		ABEntries: []ABEntry{
+			sig,
+			&AdminMinuteNumber{MinuteNumber: 1},
+			&AdminRevealHash{IdentityChainID: hash(0x11), MatryoshkaHash: hash(0x22)},
+			&AdminAddHash{IdentityChainID: hash(0x33), MatryoshkaHash: hash(0x44)},
+			&AdminIncreaseServerCount{Amount: 3},
+			&AdminAddFederatedServer{IdentityChainID: hash(0x55), DBHeight: 10},
+			&AdminAddAuditServer{IdentityChainID: hash(0x66), DBHeight: 11},
+			&AdminRemoveFederatedServer{IdentityChainID: hash(0x77), DBHeight: 12},
+			&AdminAddFederatedServerKey{IdentityChainID: hash(0x88), KeyPriority: 2, PublicKey: hash(0x99), DBHeight: 13},
+			&AdminAddFederatedServerBTCKey{IdentityChainID: hash(0xaa), KeyPriority: 3, KeyType: 1, ECDSAPublicKey: "13hikQwGStt6Urgiwbxecv5NVW6f77LP3N"},
+			&AdminServerFault{
+				Timestamp:     "1546300800000",
+				ServerID:      hash(0xcc),
+				AuditServerID: hash(0xdd),
+				VMIndex:       4,
+				DBHeight:      14,
+				Height:        5,
+				SignatureList: json.RawMessage(`{"Length":1,"List":[{"pub":"` + hash(0xee) + `","sig":"` + hash(0xff) + hash(0xff) + `"}]}`),
+			},
+			coinbase,
+			&AdminCoinbaseDescriptorCancel{DescriptorHeight: 199975, DescriptorIndex: 200},
+			&AdminAddAuthorityAddress{IdentityChainID: hash(0x13), FactoidAddress: "FA1y5ZGuHSLmf2TqNf6hVMkPiNGyQpQDTFJvDLRkKQaoPo4bmbgu"},
+			&AdminAddAuthorityEfficiency{IdentityChainID: hash(0x14), Efficiency: 5000},
+		},
+	}
+
+	p, err := ab.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(ABlock)
+	if err := decoded.UnmarshalBinary(p); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.LookupHash == "" || decoded.BackReferenceHash == "" {
+		t.Error("expected the hashes of the decoded block to be set")
+	}
+	decoded.LookupHash, decoded.BackReferenceHash = "", ""
+	if !reflect.DeepEqual(decoded, ab) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", ab, decoded)
+	}
+}
+
+func TestABlockJSONBinaryRoundTrip(t *testing.T) {
+	// the JSON form from factomd, with a Coinbase Descriptor and a Cancel
+	js := []byte(`{"header":{"prevbackrefhash":"e3549cd600cbb00d6f8bf4c505ee74f6dc5326d7aa02bb7e4b33f8f16bd6f3f5","dbheight":20000,"headerexpansionsize":0,"headerexpansionarea":"","messagecount":2,"bodysize":131,"adminchainid":"000000000000000000000000000000000000000000000000000000000000000a","chainid":"000000000000000000000000000000000000000000000000000000000000000a"},"abentries":[{"adminidtype":1,"identityadminchainid":"0000000000000000000000000000000000000000000000000000000000000000","prevdbsig":{"pub":"0426a802617848d4d16d87830fc521f4d136bb2d0c352850919c2679f189613a","sig":"a7d55725393d78a0e623141a41bfcb64956d308eeb1ae501243ad171c2ed42e62a654e138025d0439ecb5bbf594315c191fa88eedb699d9b63a426a6036d630d"}},{"adminidtype":0,"minutenumber":1},{"adminidtype":2,"identitychainid":"1111111111111111111111111111111111111111111111111111111111111111","mhash":"2222222222222222222222222222222222222222222222222222222222222222"},{"adminidtype":3,"identitychainid":"3333333333333333333333333333333333333333333333333333333333333333","mhash":"4444444444444444444444444444444444444444444444444444444444444444"},{"adminidtype":4,"amount":3},{"adminidtype":5,"identitychainid":"5555555555555555555555555555555555555555555555555555555555555555","dbheight":10},{"adminidtype":6,"identitychainid":"6666666666666666666666666666666666666666666666666666666666666666","dbheight":11},{"adminidtype":7,"identitychainid":"7777777777777777777777777777777777777777777777777777777777777777","dbheight":12},{"adminidtype":8,"identitychainid":"8888888888888888888888888888888888888888888888888888888888888888","keypriority":2,"publickey":"9999999999999999999999999999999999999999999999999999999999999999","dbheight":13},{"adminidtype":9,"identitychainid":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","keypriority":3,"keytype":1,"ecdsapublickey":"13hikQwGStt6Urgiwbxecv5NVW6f77LP3N"},{"adminidtype":11,"outputs":[{"amount":640000000000,"address":"031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f"}]},{"adminidtype":12,"descriptor_height":199975,"descriptor_index":200},{"adminidtype":13,"identitychainid":"1313131313131313131313131313131313131313131313131313131313131313","factoidaddress":"FA1y5ZGuHSLmf2TqNf6hVMkPiNGyQpQDTFJvDLRkKQaoPo4bmbgu"},{"adminidtype":14,"identitychainid":"1414141414141414141414141414141414141414141414141414141414141414","efficiency":14}],"backreferencehash":"c8ad13a2aea0f961bf73ac9e79ae8aa0d77ddf59e7d02931de7b9e53a3a20c5e","lookuphash":"e7eb4bda495dbe7657cae1525b6be78bd2fdbad952ebde506b6a97e1cf8f431e"}`)
+
+	ab := new(ABlock)
+	if err := json.Unmarshal(js, ab); err != nil {
+		t.Fatal(err)
+	}
+	p, err := ab.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the base58check ECDSAPublicKey is written as its 20 byte hash
+	key, _ := hex.DecodeString("1da3bd6be0c7ab4e3056d61b015bc199a23eec2e")
+	if !bytes.Contains(p, key) {
+		t.Errorf("expected the block to contain the key hash %x, got %x", key, p)
+	}
+	decoded := new(ABlock)
+	if err := decoded.UnmarshalBinary(p); err != nil {
+		t.Fatal(err)
+	}
+
+	// the hashes in the JSON are not of this block
+	ab.LookupHash, ab.BackReferenceHash = "", ""
+	decoded.LookupHash, decoded.BackReferenceHash = "", ""
+	if !reflect.DeepEqual(decoded, ab) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", ab, decoded)
+	}
+}
+
+func TestAdminCoinbaseDescriptorCancelBinary(t *testing.T) {
+	// factomd's CancelCoinbaseDescriptor writes the type, a varint size and
+	// then both DescriptorHeight and DescriptorIndex as varints
+	entry, _ := hex.DecodeString("0c" + "05" + "8c9a27" + "8148")
+
+	ab := &ABlock{
+		PrevBackreferenceHash: hex.EncodeToString(bytes.Repeat([]byte{0x01}, 32)),
+		DBHeight:              200000,
+		ABEntries: []ABEntry{
+			&AdminCoinbaseDescriptorCancel{DescriptorHeight: 199975, DescriptorIndex: 200},
+		},
+	}
+	p, err := ab.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(p, entry) {
+		t.Errorf("expected the block to end with %x, got %x", entry, p)
+	}
+
+	decoded := new(ABlock)
+	if err := decoded.UnmarshalBinary(p); err != nil {
+		t.Fatal(err)
+	}
+	decoded.LookupHash, decoded.BackReferenceHash = "", ""
+	if !reflect.DeepEqual(decoded, ab) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", ab, decoded)
+	}
+}
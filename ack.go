@@ -5,7 +5,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -74,19 +74,11 @@ func (e *EntryStatus) String() string {
 }
 
 // FactoidACK gets the status of a given Factoid Transaction.
-func FactoidACK(txID, fullTransaction string) (*FactoidTxStatus, error) {
+func (c *Client) FactoidACK(ctx context.Context, txID, fullTransaction string) (*FactoidTxStatus, error) {
 	params := ackRequest{Hash: txID, ChainID: "f", FullTransaction: fullTransaction}
 	req := NewJSON2Request("ack", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	eb := new(FactoidTxStatus)
-	if err := json.Unmarshal(resp.JSONResult(), eb); err != nil {
+	if err := c.factomdCall(ctx, req, eb); err != nil {
 		return nil, err
 	}
 
@@ -94,19 +86,11 @@ func FactoidACK(txID, fullTransaction string) (*FactoidTxStatus, error) {
 }
 
 // EntryCommitACK searches for an entry/chain commit with a given transaction ID.
-func EntryCommitACK(txID, fullTransaction string) (*EntryStatus, error) {
+func (c *Client) EntryCommitACK(ctx context.Context, txID, fullTransaction string) (*EntryStatus, error) {
 	params := ackRequest{Hash: txID, ChainID: "c", FullTransaction: fullTransaction}
 	req := NewJSON2Request("ack", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	eb := new(EntryStatus)
-	if err := json.Unmarshal(resp.JSONResult(), eb); err != nil {
+	if err := c.factomdCall(ctx, req, eb); err != nil {
 		return nil, err
 	}
 
@@ -114,19 +98,11 @@ func EntryCommitACK(txID, fullTransaction string) (*EntryStatus, error) {
 }
 
 // EntryRevealACK will take the entryhash and search for the entry and the commit
-func EntryRevealACK(entryhash, fullTransaction, chainiID string) (*EntryStatus, error) {
+func (c *Client) EntryRevealACK(ctx context.Context, entryhash, fullTransaction, chainiID string) (*EntryStatus, error) {
 	params := ackRequest{Hash: entryhash, ChainID: chainiID, FullTransaction: fullTransaction}
 	req := NewJSON2Request("ack", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	eb := new(EntryStatus)
-	if err := json.Unmarshal(resp.JSONResult(), eb); err != nil {
+	if err := c.factomdCall(ctx, req, eb); err != nil {
 		return nil, err
 	}
 
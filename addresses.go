@@ -94,6 +94,33 @@ func IsValidAddress(s string) bool {
 	return false
 }
 
+// FactoidAddressFromRCDHash returns the public Factoid Address (FA...) of an
+// RCD Hash.
+func FactoidAddressFromRCDHash(rcdHash []byte) string {
+	buf := new(bytes.Buffer)
+
+	// FC address prefix
+	buf.Write(fcPubPrefix)
+
+	// RCD Hash
+	buf.Write(rcdHash)
+
+	// Checksum
+	check := shad(buf.Bytes())[:ChecksumLength]
+	buf.Write(check)
+
+	return base58.Encode(buf.Bytes())
+}
+
+// RCDHashFromFactoidAddress returns the RCD Hash of a public Factoid Address.
+func RCDHashFromFactoidAddress(s string) ([]byte, error) {
+	if AddressStringType(s) != FactoidPub {
+		return nil, ErrInvalidAddress
+	}
+	p := base58.Decode(s)
+	return p[PrefixLength:BodyLength], nil
+}
+
 // ECAddress is an Entry Credit public/secret key pair.
 type ECAddress struct {
 	Pub *[ed.PublicKeySize]byte
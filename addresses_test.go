@@ -239,6 +239,27 @@ func TestGetFactoidAddress(t *testing.T) {
 	// ? test factoid key validity here
 }
 
+func TestFactoidAddressFromRCDHash(t *testing.T) {
+	fct, err := GetFactoidAddress("Fs1KWJrpLdfucvmYwN2nWrwepLn8ercpMbzXshd1g8zyhKXLVLWj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := FactoidAddressFromRCDHash(fct.RCDHash()); s != fct.String() {
+		t.Errorf("expected %s, got %s", fct.String(), s)
+	}
+	h, err := RCDHashFromFactoidAddress(fct.String())
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(h, fct.RCDHash()) {
+		t.Errorf("expected RCD Hash %x, got %x", fct.RCDHash(), h)
+	}
+	if _, err := RCDHashFromFactoidAddress(fct.SecString()); err != ErrInvalidAddress {
+		t.Errorf("expected %v, got %v", ErrInvalidAddress, err)
+	}
+}
+
 func TestMakeFactoidAddressFromMnemonic(t *testing.T) {
 	m := "yellow yellow yellow yellow yellow yellow yellow yellow yellow yellow yellow yellow"
 	cannonAdr := "FA3cih2o2tjEUsnnFR4jX1tQXPpSXFwsp3rhVp6odL5PNCHWvZV1"
@@ -2,11 +2,22 @@ package factom
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+var ErrInvalidAnchor = errors.New("invalid anchor")
+
+// setAnchorSelector is the function selector of setAnchor(uint256,uint256) on
+// the Factom Ethereum anchor contract, the first 4 bytes of its Keccak-256
+// hash.
+var setAnchorSelector = []byte{0xbb, 0xcc, 0x0c, 0x80}
+
 // Anchors is an anchors response from factomd.
 // Note that Ethereum or Bitcoin can be nil
 type Anchors struct {
@@ -86,14 +97,76 @@ func (a *Anchors) String() string {
 
 // UnmarshalJSON is an unmarshaller that handles the variable response from factomd
 func (a *Anchors) UnmarshalJSON(data []byte) error {
-	type tmp *Anchors // unmarshal into a new type to prevent infinite loop
+	type tmp Anchors // unmarshal into a new type to prevent infinite loop
 	// json can't unmarshal a bool into a struct, but it can recognize a null pointer
 	data = bytes.Replace(data, []byte("\"ethereum\":false"), []byte("\"ethereum\":null"), -1)
 	data = bytes.Replace(data, []byte("\"bitcoin\":false"), []byte("\"bitcoin\":null"), -1)
-	return json.Unmarshal(data, tmp(a))
+	return json.Unmarshal(data, (*tmp)(a))
+}
+
+// Verify checks that the KeyMR of a Directory Block in the anchor window folds
+// through the MerkleBranch to the WindowMR. It works offline; the anchor is
+// only as trusted as the WindowMR, see VerifyTransactionInput.
+func (a *AnchorEthereum) Verify(dblockKeyMR string) error {
+	if a.DBHeightMin > a.DBHeightMax {
+		return fmt.Errorf("%w: DBHeightMin %d is above DBHeightMax %d",
+			ErrInvalidAnchor, a.DBHeightMin, a.DBHeightMax)
+	}
+	windowMR, err := hex.DecodeString(a.WindowMR)
+	if err != nil || len(windowMR) != 32 {
+		return fmt.Errorf("%w: WindowMR %q is not a 32 byte hash", ErrInvalidAnchor, a.WindowMR)
+	}
+	node, err := hex.DecodeString(dblockKeyMR)
+	if err != nil || len(node) != 32 {
+		return fmt.Errorf("dblockKeyMR %q is not a 32 byte hash", dblockKeyMR)
+	}
+
+	for i, b := range a.MerkleBranch {
+		if node, err = merkleStep(node, b.Left, b.Right, b.Top); err != nil {
+			return fmt.Errorf("%w: MerkleBranch[%d] %v", ErrInvalidAnchor, i, err)
+		}
+	}
+
+	if !bytes.Equal(node, windowMR) {
+		return fmt.Errorf("%w: MerkleBranch ends in %x, not the WindowMR",
+			ErrInvalidAnchor, node)
+	}
+	return nil
 }
 
-func getAnchors(hash string, height int64) (*Anchors, error) {
+// VerifyTransactionInput checks that the input data of an Ethereum
+// transaction, as found at TxID, is a call to setAnchor on the anchor contract
+// that commits to the WindowMR of the anchor at DBHeightMax, the last
+// Directory Block of the window. The contract does not record DBHeightMin.
+func (a *AnchorEthereum) VerifyTransactionInput(input []byte) error {
+	if len(input) != len(setAnchorSelector)+64 {
+		return fmt.Errorf("%w: input is %d bytes, not a call to setAnchor",
+			ErrInvalidAnchor, len(input))
+	}
+	if !bytes.Equal(input[:4], setAnchorSelector) {
+		return fmt.Errorf("%w: input calls function %x, not setAnchor",
+			ErrInvalidAnchor, input[:4])
+	}
+	if a.DBHeightMin > a.DBHeightMax {
+		return fmt.Errorf("%w: DBHeightMin %d is above DBHeightMax %d",
+			ErrInvalidAnchor, a.DBHeightMin, a.DBHeightMax)
+	}
+
+	// the arguments are two 32 byte big endian words
+	height := input[4:36]
+	if !bytes.Equal(height[:24], make([]byte, 24)) ||
+		binary.BigEndian.Uint64(height[24:]) != uint64(a.DBHeightMax) {
+		return fmt.Errorf("%w: input anchors height %x, not DBHeightMax %d",
+			ErrInvalidAnchor, height, a.DBHeightMax)
+	}
+	if root := hex.EncodeToString(input[36:]); !strings.EqualFold(root, a.WindowMR) {
+		return fmt.Errorf("%w: input anchors %s, not WindowMR %s",
+			ErrInvalidAnchor, root, a.WindowMR)
+	}
+	return nil
+}
+
+func (c *Client) getAnchors(ctx context.Context, hash string, height int64) (*Anchors, error) {
 	var params interface{}
 	if hash != "" {
 		params = hashRequest{Hash: hash}
@@ -101,16 +174,8 @@ func getAnchors(hash string, height int64) (*Anchors, error) {
 		params = heightRequest{Height: height}
 	}
 	req := NewJSON2Request("anchors", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
 	var res Anchors
-	err = json.Unmarshal(resp.Result, &res)
-	if err != nil {
+	if err := c.factomdCall(ctx, req, &res); err != nil {
 		return nil, err
 	}
 
@@ -121,12 +186,12 @@ func getAnchors(hash string, height int64) (*Anchors, error) {
 // Hash can be entry hash, entry block keymr, factoid block keymr,
 // admin block lookup hash, entry credit block header hash, or
 // directory block keymr
-func GetAnchors(hash string) (*Anchors, error) {
-	return getAnchors(hash, 0)
+func (c *Client) GetAnchors(ctx context.Context, hash string) (*Anchors, error) {
+	return c.getAnchors(ctx, hash, 0)
 }
 
 // GetAnchorsByHeight retrieves the bitcoin and ethereum anchors for
 // a specific height
-func GetAnchorsByHeight(height int64) (*Anchors, error) {
-	return getAnchors("", height)
+func (c *Client) GetAnchorsByHeight(ctx context.Context, height int64) (*Anchors, error) {
+	return c.getAnchors(ctx, "", height)
 }
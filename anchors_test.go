@@ -1,6 +1,9 @@
 package factom
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -105,3 +108,58 @@ Ethereum {
 	}
 
 }
+
+func TestAnchorEthereumVerify(t *testing.T) {
+	js := []byte(`{"recordheight":200001,"dbheightmax":200000,"dbheightmin":199001,"windowmr":"935480547a2545161438da05136ec4238d88e7f8e1075687d8292fcafc1d0b22","merklebranch":[{"left":"a92a4460a9555c8b57a282e7ad4514d0f5aa8a612963da79db0e7cede6299bcd","right":"ce86fc790dd1462aea255adaa64e2f21c871995df2c2c119352d869fa1d7269f","top":"bbbf494fff20d8fbada47980498a96e49fba920fd1ca4e0586af4961e4318354"},{"left":"b41ffe5f9b710edf6bcf1a6d3e92685155411111d640f2b2224ab15f04c46161","right":"bbbf494fff20d8fbada47980498a96e49fba920fd1ca4e0586af4961e4318354","top":"ca1e7d59dc295c48ebddf6c068826584fb5adb38798f5cac7f964509627825f5"},{"left":"c919c0fb94900f3016c75a7f9f992c79aa7db126db6c6c3b1af92bbee174bda6","right":"ca1e7d59dc295c48ebddf6c068826584fb5adb38798f5cac7f964509627825f5","top":"3c8e97e32b4dbc0e4d82f066217492ca72ca722b91dbc764c311c39a9196bdda"},{"left":"3c8e97e32b4dbc0e4d82f066217492ca72ca722b91dbc764c311c39a9196bdda","right":"3c8e97e32b4dbc0e4d82f066217492ca72ca722b91dbc764c311c39a9196bdda","top":"5713abb24c8a8480f5c04bfdb8668a93e8b0a24a0562afbc7f362d665938a89e"},{"left":"5713abb24c8a8480f5c04bfdb8668a93e8b0a24a0562afbc7f362d665938a89e","right":"5713abb24c8a8480f5c04bfdb8668a93e8b0a24a0562afbc7f362d665938a89e","top":"a7ab3a72f5e754f3e10f04b9f7f2e50f36c688776b521918a755e62fd6c2da34"},{"left":"5e42d2f5ba59338b317369bbc53fbb70c094364ae8c15c9a291dad65dec9d839","right":"a7ab3a72f5e754f3e10f04b9f7f2e50f36c688776b521918a755e62fd6c2da34","top":"bc5c336d7e53b6d9f2832038c69a78eb7300bd539b6002d44f450a4d2c979aa3"},{"left":"31041410eb7bd6a9fabe974a0f4eabe477ffbbf5806fdaee5da98faff63b8142","right":"bc5c336d7e53b6d9f2832038c69a78eb7300bd539b6002d44f450a4d2c979aa3","top":"f9b5967e2acadfac93bc2141ee9f85b0649f89452e44e4f8b1cbe877457a0da4"},{"left":"04aa1b2d35ea99becd92bcfccc7406aea37287a58425b83349d0fc8cc7bec443","right":"f9b5967e2acadfac93bc2141ee9f85b0649f89452e44e4f8b1cbe877457a0da4","top":"77eca087628e243bf2914162be6c4aefaf736ed56e8c125b955cef3c48a1ab4b"},{"left":"be8394e3f3a205ad37afb8af778695e2914f071b3d215e586a93288fd692eb00","right":"77eca087628e243bf2914162be6c4aefaf736ed56e8c125b955cef3c48a1ab4b","top":"12aa5f990bd209dfaa83d9ea050e96e3b44e54ffee02252028fe969da3ab690d"},{"left":"2bdead51afe10d07c5dc5b29351fedd4a13849261359a6f22100dcc7ff50543a","right":"12aa5f990bd209dfaa83d9ea050e96e3b44e54ffee02252028fe969da3ab690d","top":"935480547a2545161438da05136ec4238d88e7f8e1075687d8292fcafc1d0b22"}],"contractaddress":"0xfac701d9554a008e48b6307fb90457ba3959e8a8","txid":"0xd57492c9d505e3052c454acdfc3768bc3eb8859c91829654346503ef2dcb6a23","blockhash":"0x49e8f3c394079e6c3964fdf943ea9759475d4ca5aec90c9a695be07efdd88d32","txindex":31}`)
+
+	a := new(AnchorEthereum)
+	if err := json.Unmarshal(js, a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Verify("ce86fc790dd1462aea255adaa64e2f21c871995df2c2c119352d869fa1d7269f"); err != nil {
+		t.Error(err)
+	}
+	if err := a.Verify("a92a4460a9555c8b57a282e7ad4514d0f5aa8a612963da79db0e7cede6299bcd"); err != nil {
+		t.Error(err)
+	}
+	if err := a.Verify("b41ffe5f9b710edf6bcf1a6d3e92685155411111d640f2b2224ab15f04c46161"); !errors.Is(err, ErrInvalidAnchor) {
+		t.Errorf("expected %v, got %v", ErrInvalidAnchor, err)
+	}
+
+	a.MerkleBranch[4].Top = a.MerkleBranch[4].Left
+	if err := a.Verify("ce86fc790dd1462aea255adaa64e2f21c871995df2c2c119352d869fa1d7269f"); !errors.Is(err, ErrInvalidAnchor) {
+		t.Errorf("expected %v, got %v", ErrInvalidAnchor, err)
+	}
+	a.MerkleBranch = a.MerkleBranch[:3]
+	if err := a.Verify("ce86fc790dd1462aea255adaa64e2f21c871995df2c2c119352d869fa1d7269f"); !errors.Is(err, ErrInvalidAnchor) {
+		t.Errorf("expected %v, got %v", ErrInvalidAnchor, err)
+	}
+}
+
+func TestAnchorEthereumVerifyTransactionInput(t *testing.T) {
+	a := &AnchorEthereum{
+		DBHeightMax: 200000,
+		DBHeightMin: 199001,
+		WindowMR:    "935480547a2545161438da05136ec4238d88e7f8e1075687d8292fcafc1d0b22",
+	}
+	input, _ := hex.DecodeString("bbcc0c80" +
+		"0000000000000000000000000000000000000000000000000000000000030d40" +
+		"935480547a2545161438da05136ec4238d88e7f8e1075687d8292fcafc1d0b22")
+
+	if err := a.VerifyTransactionInput(input); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string][]byte{
+		"Length":   input[:67],
+		"Selector": append([]byte{0xa9, 0x05, 0x9c, 0xbb}, input[4:]...),
+		"Height":   append(append(append([]byte{}, input[:35]...), 0x41), input[36:]...),
+		"WindowMR": append(append([]byte{}, input[:67]...), 0x23),
+	}
+	for name, input := range tests {
+		if err := a.VerifyTransactionInput(input); !errors.Is(err, ErrInvalidAnchor) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidAnchor, err)
+		}
+	}
+}
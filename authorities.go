@@ -5,7 +5,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -60,22 +60,13 @@ func (k *AnchorSigningKey) String() string {
 }
 
 // GetAuthorities retrieves a list of the known athorities from factomd.
-func GetAuthorities() ([]*Authority, error) {
+func (c *Client) GetAuthorities(ctx context.Context) ([]*Authority, error) {
 	req := NewJSON2Request("authorities", APICounter(), nil)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	// create a temporary type to unmarshal the json object
 	a := new(struct {
 		Authorities []*Authority `json:"authorities"`
 	})
-
-	if err := json.Unmarshal(resp.JSONResult(), a); err != nil {
+	if err := c.factomdCall(ctx, req, a); err != nil {
 		return nil, err
 	}
 
@@ -5,7 +5,8 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 )
 
 type MultiBalanceResponse struct {
@@ -20,23 +21,15 @@ type MultiBalanceResponse struct {
 
 // GetECBalance returns the Entry Credit balance of a given Entry
 // Credit Public Address.
-func GetECBalance(addr string) (int64, error) {
+func (c *Client) GetECBalance(ctx context.Context, addr string) (int64, error) {
 	type balanceResponse struct {
 		Balance int64 `json:"balance"`
 	}
 
 	params := addressRequest{Address: addr}
 	req := NewJSON2Request("entry-credit-balance", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return -1, err
-	}
-	if resp.Error != nil {
-		return -1, resp.Error
-	}
-
 	balance := new(balanceResponse)
-	if err := json.Unmarshal(resp.JSONResult(), balance); err != nil {
+	if err := c.factomdCall(ctx, req, balance); err != nil {
 		return -1, err
 	}
 
@@ -45,52 +38,64 @@ func GetECBalance(addr string) (int64, error) {
 
 // GetFactoidBalance returns the balance in factoshi (factoid * 1e8) of a given
 // Factoid Public Address.
-func GetFactoidBalance(addr string) (int64, error) {
+func (c *Client) GetFactoidBalance(ctx context.Context, addr string) (int64, error) {
 	type balanceResponse struct {
 		Balance int64 `json:"balance"`
 	}
 
 	params := addressRequest{Address: addr}
 	req := NewJSON2Request("factoid-balance", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return -1, err
-	}
-	if resp.Error != nil {
-		return -1, resp.Error
-	}
-
 	balance := new(balanceResponse)
-	if err := json.Unmarshal(resp.JSONResult(), balance); err != nil {
+	if err := c.factomdCall(ctx, req, balance); err != nil {
 		return -1, err
 	}
 
 	return balance.Balance, nil
 }
 
-// GetBalanceTotals return the total value of Factoids and Entry Credits in the
-// wallet according to the the server acknowledgement and the value saved in the
-// blockchain.
-func GetBalanceTotals() (fs, fa, es, ea int64, err error) {
-	type multiBalanceResponse struct {
-		FactoidAccountBalances struct {
-			Ack   int64 `json:"ack"`
-			Saved int64 `json:"saved"`
-		} `json:"fctaccountbalances"`
-		EntryCreditAccountBalances struct {
-			Ack   int64 `json:"ack"`
-			Saved int64 `json:"saved"`
-		} `json:"ecaccountbalances"`
-	}
+// AccountBalances is a total balance of the addresses in the wallet according
+// to the server acknowledgement and the value saved in the blockchain.
+type AccountBalances struct {
+	Ack   int64 `json:"ack"`
+	Saved int64 `json:"saved"`
+}
+
+// WalletBalances are the total Factoid and Entry Credit balances of the
+// addresses in the wallet.
+type WalletBalances struct {
+	FactoidAccountBalances     AccountBalances `json:"fctaccountbalances"`
+	EntryCreditAccountBalances AccountBalances `json:"ecaccountbalances"`
+}
+
+func (b *WalletBalances) String() string {
+	var s string
+
+	s += fmt.Sprintln("FactoidAck:", b.FactoidAccountBalances.Ack)
+	s += fmt.Sprintln("FactoidSaved:", b.FactoidAccountBalances.Saved)
+	s += fmt.Sprintln("EntryCreditAck:", b.EntryCreditAccountBalances.Ack)
+	s += fmt.Sprintln("EntryCreditSaved:", b.EntryCreditAccountBalances.Saved)
+
+	return s
+}
 
+// GetWalletBalances requests the total Factoid and Entry Credit balances of
+// the addresses in the wallet.
+func (w *WalletClient) GetWalletBalances(ctx context.Context) (*WalletBalances, error) {
 	req := NewJSON2Request("wallet-balances", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return
+
+	balances := new(WalletBalances)
+	if err := w.walletCall(ctx, req, balances); err != nil {
+		return nil, err
 	}
 
-	balances := new(multiBalanceResponse)
-	err = json.Unmarshal(resp.JSONResult(), balances)
+	return balances, nil
+}
+
+// GetBalanceTotals return the total value of Factoids and Entry Credits in the
+// wallet according to the the server acknowledgement and the value saved in the
+// blockchain.
+func (w *WalletClient) GetBalanceTotals(ctx context.Context) (fs, fa, es, ea int64, err error) {
+	balances, err := w.GetWalletBalances(ctx)
 	if err != nil {
 		return
 	}
@@ -105,21 +110,15 @@ func GetBalanceTotals() (fs, fa, es, ea int64, err error) {
 
 // GetMultipleFCTBalances returns balances for multiple Factoid Addresses from
 // the factomd API.
-func GetMultipleFCTBalances(fas ...string) (*MultiBalanceResponse, error) {
+func (c *Client) GetMultipleFCTBalances(ctx context.Context, fas ...string) (*MultiBalanceResponse, error) {
 	type multiAddressRequest struct {
 		Addresses []string `json:"addresses"`
 	}
 
 	params := multiAddressRequest{fas}
 	req := NewJSON2Request("multiple-fct-balances", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	balances := new(MultiBalanceResponse)
-	err = json.Unmarshal(resp.JSONResult(), balances)
-	if err != nil {
+	if err := c.factomdCall(ctx, req, balances); err != nil {
 		return nil, err
 	}
 
@@ -128,21 +127,15 @@ func GetMultipleFCTBalances(fas ...string) (*MultiBalanceResponse, error) {
 
 // GetMultipleECBalances returns balances for multiple Entry Credit Addresses
 // from the factomd API.
-func GetMultipleECBalances(ecs ...string) (*MultiBalanceResponse, error) {
+func (c *Client) GetMultipleECBalances(ctx context.Context, ecs ...string) (*MultiBalanceResponse, error) {
 	type multiAddressRequest struct {
 		Addresses []string `json:"addresses"`
 	}
 
 	params := multiAddressRequest{ecs}
 	req := NewJSON2Request("multiple-ec-balances", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	balances := new(MultiBalanceResponse)
-	err = json.Unmarshal(resp.JSONResult(), balances)
-	if err != nil {
+	if err := c.factomdCall(ctx, req, balances); err != nil {
 		return nil, err
 	}
 
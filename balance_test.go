@@ -159,3 +159,37 @@ func TestGetFactoidBalance(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestGetWalletBalances(t *testing.T) {
+	walletdResponse := `{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"result": {
+			"fctaccountbalances": {"ack": 2000000000, "saved": 1000000000},
+			"ecaccountbalances": {"ack": 40, "saved": 20}
+		}
+	}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, walletdResponse)
+	}))
+	defer ts.Close()
+
+	SetWalletServer(ts.URL[7:])
+
+	b, err := GetWalletBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.FactoidAccountBalances.Ack != 2000000000 || b.EntryCreditAccountBalances.Saved != 20 {
+		t.Errorf("unexpected wallet balances\n%s", b)
+	}
+
+	fs, fa, es, ea, err := GetBalanceTotals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs != 1000000000 || fa != 2000000000 || es != 20 || ea != 40 {
+		t.Errorf("unexpected balance totals %d %d %d %d", fs, fa, es, ea)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrBatchUnsupported is returned when the API server does not answer a
+	// batch request with a batch response. Callers may fall back to sending
+	// the requests one at a time.
+	ErrBatchUnsupported = errors.New("API server does not support batch requests")
+)
+
+// SendBatch sends a batch of json objects to factomd in a single request. The
+// responses are returned in the same order as the requests. A request that
+// failed on the server has its response Error set; an error is only returned
+// if the batch as a whole failed. Every request of the batch passes through
+// the Client's Middleware, and requests found in the Client's Cache are not
+// sent.
+func (c *Client) SendBatch(ctx context.Context, reqs []*JSON2Request) ([]*JSON2Response, error) {
+	send := func(ctx context.Context, reqs []*JSON2Request, header http.Header) ([]*JSON2Response, error) {
+		idempotent := true
+		for _, req := range reqs {
+			idempotent = idempotent && IsIdempotentMethod(req.Method)
+		}
+		return sendBatch(ctx, reqs, func(ctx context.Context, j []byte) ([]byte, error) {
+			var body []byte
+			err := c.factomdSend(ctx, apiPath, idempotent, header, j, readAll(&body))
+			return body, err
+		})
+	}
+
+	// the requests found in the cache are answered by its Middleware
+	mws := c.middleware()
+	if len(mws) == 0 {
+		return send(ctx, reqs, make(http.Header))
+	}
+	return batchMiddleware(ctx, mws, reqs, send)
+}
+
+// SendBatch sends a batch of json objects to factom-walletd in a single
+// request. The responses are returned in the same order as the requests. A
+// request that failed on the server has its response Error set; an error is
+// only returned if the batch as a whole failed. Every request of the batch
+// passes through the WalletClient's Middleware.
+func (w *WalletClient) SendBatch(ctx context.Context, reqs []*JSON2Request) ([]*JSON2Response, error) {
+	send := func(ctx context.Context, reqs []*JSON2Request, header http.Header) ([]*JSON2Response, error) {
+		return sendBatch(ctx, reqs, func(ctx context.Context, j []byte) ([]byte, error) {
+			var body []byte
+			err := w.walletPost(ctx, header, j, readAll(&body))
+			return body, err
+		})
+	}
+	if len(w.Middleware) == 0 {
+		return send(ctx, reqs, make(http.Header))
+	}
+	return batchMiddleware(ctx, w.Middleware, reqs, send)
+}
+
+// sendBatch posts the batch of requests and matches the responses back to the
+// requests by their IDs. Every request must have an ID; a nil ID is sent as
+// "id":null, which is not a notification, and its response could not be
+// matched.
+func sendBatch(
+	ctx context.Context,
+	reqs []*JSON2Request,
+	post func(context.Context, []byte) ([]byte, error),
+) ([]*JSON2Response, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	ids := make(map[string]int)
+	for i, req := range reqs {
+		if req.ID == nil {
+			return nil, fmt.Errorf("request %d (%s) of the batch has no id", i, req.Method)
+		}
+		id, err := batchID(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ids[id]; ok {
+			return nil, fmt.Errorf("duplicate request id %s in batch", id)
+		}
+		ids[id] = i
+	}
+
+	j, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := post(ctx, j)
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		switch herr.StatusCode {
+		case http.StatusBadRequest, http.StatusNotFound,
+			http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return nil, fmt.Errorf("%w: %v", ErrBatchUnsupported, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// a server without batch support answers with a single error object
+	if b := bytes.TrimSpace(body); len(b) == 0 || b[0] != '[' {
+		r := NewJSON2Response()
+		if err := json.Unmarshal(b, r); err == nil && r.Error != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBatchUnsupported, r.Error)
+		}
+		return nil, ErrBatchUnsupported
+	}
+
+	rs := make([]*JSON2Response, 0)
+	if err := json.Unmarshal(body, &rs); err != nil {
+		return nil, err
+	}
+
+	resps := make([]*JSON2Response, len(reqs))
+	for _, r := range rs {
+		id, err := batchID(r.ID)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := ids[id]
+		if !ok {
+			return nil, fmt.Errorf("unexpected response id %s in batch", id)
+		}
+		resps[i] = r
+	}
+	for i, req := range reqs {
+		if resps[i] == nil {
+			return nil, fmt.Errorf("no response for request id %v in batch", req.ID)
+		}
+	}
+
+	return resps, nil
+}
+
+// batchID returns the JSON encoding of a request or response ID so that an ID
+// sent as an int matches the float64 it is decoded into.
+func batchID(id interface{}) (string, error) {
+	p, err := json.Marshal(id)
+	if err != nil {
+		return "", err
+	}
+	return string(p), nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+func TestSendFactomdBatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs := make([]*JSON2Request, 0)
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Error(err)
+			return
+		}
+
+		// answer out of order to check that responses are matched by id
+		resps := make([]*JSON2Response, 0)
+		for i := len(reqs) - 1; i >= 0; i-- {
+			resp := NewJSON2Response()
+			resp.ID = reqs[i].ID
+			if reqs[i].Method == "heights" {
+				resp.Result = json.RawMessage(`{"directoryblockheight":10}`)
+			} else {
+				resp.Error = NewJSONError(-32601, "Method not found", nil)
+			}
+			resps = append(resps, resp)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer ts.Close()
+
+	SetFactomdServer(ts.URL[7:])
+
+	reqs := []*JSON2Request{
+		NewJSON2Request("heights", 1, nil),
+		NewJSON2Request("bad-method", 2, nil),
+		NewJSON2Request("heights", 3, nil),
+	}
+	resps, err := SendFactomdBatch(reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resps) != len(reqs) {
+		t.Fatalf("expected %d responses, got %d", len(reqs), len(resps))
+	}
+	for i, resp := range resps {
+		if fmt.Sprint(resp.ID) != fmt.Sprint(reqs[i].ID) {
+			t.Errorf("response %d has id %v, expected %v", i, resp.ID, reqs[i].ID)
+		}
+	}
+	if resps[0].Error != nil || resps[2].Error != nil {
+		t.Errorf("unexpected errors: %v %v", resps[0].Error, resps[2].Error)
+	}
+	if resps[1].Error == nil {
+		t.Error("expected an error for the bad method")
+	}
+}
+
+func TestSendBatchNilID(t *testing.T) {
+	posts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `[{"jsonrpc":"2.0","id":1,"result":{}},{"jsonrpc":"2.0","id":null,"result":{}}]`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	_, err := c.SendBatch(context.Background(), []*JSON2Request{
+		NewJSON2Request("heights", 1, nil),
+		NewJSON2Request("heights", nil, nil),
+	})
+	if err == nil || !strings.Contains(err.Error(), "request 1 (heights) of the batch has no id") {
+		t.Errorf("expected an error for the request without an id, got %v", err)
+	}
+	if posts != 0 {
+		t.Errorf("expected the batch not to be sent, made %d requests", posts)
+	}
+}
+
+func TestGetAllEBlockEntriesBatch(t *testing.T) {
+	for _, batch := range []bool{true, false} {
+		var posts int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			posts++
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+
+			if bytes.HasPrefix(body, []byte("[")) {
+				if !batch {
+					fmt.Fprintln(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`)
+					return
+				}
+				reqs := make([]*JSON2Request, 0)
+				json.Unmarshal(body, &reqs)
+				resps := make([]*JSON2Response, 0)
+				for _, req := range reqs {
+					resp := NewJSON2Response()
+					resp.ID = req.ID
+					resp.Result = entryResult(req)
+					resps = append(resps, resp)
+				}
+				json.NewEncoder(w).Encode(resps)
+				return
+			}
+
+			req := new(JSON2Request)
+			json.Unmarshal(body, req)
+			resp := NewJSON2Response()
+			resp.ID = req.ID
+			switch req.Method {
+			case "entry-block":
+				resp.Result = json.RawMessage(`{"header":{"prevkeymr":"0000000000000000000000000000000000000000000000000000000000000000"},"entrylist":[
+					{"entryhash":"0000000000000000000000000000000000000000000000000000000000000001"},
+					{"entryhash":"0000000000000000000000000000000000000000000000000000000000000002"},
+					{"entryhash":"0000000000000000000000000000000000000000000000000000000000000003"}
+				]}`)
+			case "entry":
+				resp.Result = entryResult(req)
+			}
+			json.NewEncoder(w).Encode(resp)
+		}))
+
+		c := NewClient(ts.URL)
+		es, err := c.GetAllEBlockEntries(context.Background(), "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		ts.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(es) != 3 {
+			t.Fatalf("expected 3 entries, got %d", len(es))
+		}
+		for i, e := range es {
+			if want := fmt.Sprintf("content %d", i+1); string(e.Content) != want {
+				t.Errorf("expected entry %q, got %q", want, e.Content)
+			}
+		}
+
+		// the batch is one request for the EBlock and one for the Entries,
+		// the fallback tries the batch and then requests each Entry
+		expected := 2
+		if !batch {
+			expected = 5
+		}
+		if posts != expected {
+			t.Errorf("batch %v: expected %d requests, made %d", batch, expected, posts)
+		}
+	}
+}
+
+// entryResult returns an entry API result whose content is built from the
+// last digit of the requested hash.
+func entryResult(req *JSON2Request) json.RawMessage {
+	params := new(struct {
+		Hash string `json:"hash"`
+	})
+	json.Unmarshal(req.Params, params)
+	e := NewEntryFromStrings("", "content "+params.Hash[63:])
+	p, _ := json.Marshal(e)
+	return p
+}
@@ -5,7 +5,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -54,19 +54,11 @@ func (f *BlockByHeightRawResponse) String() string {
 
 // GetBlockByHeightRaw fetches the specified block type by height
 // Deprecated: use ablock, dblock, eblock, ecblock and fblock instead.
-func GetBlockByHeightRaw(blockType string, height int64) (*BlockByHeightRawResponse, error) {
+func (c *Client) GetBlockByHeightRaw(ctx context.Context, blockType string, height int64) (*BlockByHeightRawResponse, error) {
 	params := heightRequest{Height: height, NoRaw: false} // include raw
 	req := NewJSON2Request(fmt.Sprintf("%vblock-by-height", blockType), APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	block := new(BlockByHeightRawResponse)
-	if err := json.Unmarshal(resp.JSONResult(), block); err != nil {
+	if err := c.factomdCall(ctx, req, block); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,282 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores the results of factomd API requests for data that never
+// changes once it is in the blockchain, such as an Entry by its hash or a
+// Directory Block by its KeyMR or height. The keys are hex encoded hashes of
+// the request method and params.
+//
+// A Cache must be safe for concurrent use by multiple goroutines.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, value []byte)
+}
+
+// cacheableMethods are the factomd API methods whose results never change.
+// Requests for blocks by height only succeed for saved blocks, and errors are
+// never cached.
+var cacheableMethods = map[string]bool{
+	"entry":             true,
+	"entry-block":       true,
+	"directory-block":   true,
+	"factoid-block":     true,
+	"entrycredit-block": true,
+	"admin-block":       true,
+	"dblock-by-height":  true,
+	"fblock-by-height":  true,
+	"ecblock-by-height": true,
+	"ablock-by-height":  true,
+}
+
+// cacheKey returns the cache key of a request, or false if the result of the
+// request may not be cached.
+func cacheKey(req *JSON2Request) (string, bool) {
+	if !cacheableMethods[req.Method] {
+		return "", false
+	}
+	h := sha256.New()
+	h.Write([]byte(req.Method))
+	h.Write([]byte{0})
+	h.Write(req.Params)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// cacheGet returns the cached response to a request, or nil.
+func (c *Client) cacheGet(req *JSON2Request) *JSON2Response {
+	if c.Cache == nil {
+		return nil
+	}
+	key, ok := cacheKey(req)
+	if !ok {
+		return nil
+	}
+	result, ok := c.Cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	resp := NewJSON2Response()
+	resp.ID = req.ID
+	resp.Result = result
+	return resp
+}
+
+// cachePut stores a successful response to a request in the cache. A null
+// result, such as for a block that is not saved yet, is not stored.
+func (c *Client) cachePut(req *JSON2Request, resp *JSON2Response) {
+	if c.Cache == nil || resp == nil || resp.Error != nil {
+		return
+	}
+	if r := bytes.TrimSpace(resp.Result); len(r) == 0 || bytes.Equal(r, []byte("null")) {
+		return
+	}
+	if key, ok := cacheKey(req); ok {
+		c.Cache.Put(key, resp.Result)
+	}
+}
+
+// cacheMiddleware answers the requests found in the Client's Cache and stores
+// the results of the others. It is the innermost Middleware of a Client with
+// a Cache, so the Client's own Middleware sees the cached requests too.
+func (c *Client) cacheMiddleware(next Handler) Handler {
+	return func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+		if resp := c.cacheGet(req); resp != nil {
+			return resp, nil
+		}
+		resp, err := next(ctx, req, header)
+		if err != nil {
+			return nil, err
+		}
+		c.cachePut(req, resp)
+		return resp, nil
+	}
+}
+
+// middleware returns the Middleware of the Client's API requests: its own
+// Middleware followed by the cacheMiddleware if it has a Cache.
+func (c *Client) middleware() []Middleware {
+	if c.Cache == nil {
+		return c.Middleware
+	}
+	mws := make([]Middleware, 0, len(c.Middleware)+1)
+	mws = append(mws, c.Middleware...)
+	return append(mws, c.cacheMiddleware)
+}
+
+// MemoryCache is an in-memory Cache that holds at most MaxBytes of results,
+// evicting the least recently used results first.
+type MemoryCache struct {
+	MaxBytes int64
+
+	lock  sync.Mutex
+	size  int64
+	lru   *list.List
+	items map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCache creates a new MemoryCache holding at most maxBytes of
+// results.
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	m := new(MemoryCache)
+	m.MaxBytes = maxBytes
+	m.lru = list.New()
+	m.items = make(map[string]*list.Element)
+	return m
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	e, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	m.lru.MoveToFront(e)
+	return e.Value.(*memoryCacheItem).value, true
+}
+
+func (m *MemoryCache) Put(key string, value []byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// a result larger than the cache would evict everything else
+	if int64(len(value)) > m.MaxBytes {
+		return
+	}
+
+	if e, ok := m.items[key]; ok {
+		item := e.Value.(*memoryCacheItem)
+		m.size += int64(len(value) - len(item.value))
+		item.value = value
+		m.lru.MoveToFront(e)
+	} else {
+		m.items[key] = m.lru.PushFront(&memoryCacheItem{key: key, value: value})
+		m.size += int64(len(value))
+	}
+
+	for m.size > m.MaxBytes {
+		e := m.lru.Back()
+		item := e.Value.(*memoryCacheItem)
+		m.lru.Remove(e)
+		delete(m.items, item.key)
+		m.size -= int64(len(item.value))
+	}
+}
+
+// Len returns the number of results in the cache.
+func (m *MemoryCache) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.items)
+}
+
+// Size returns the number of bytes of results in the cache.
+func (m *MemoryCache) Size() int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.size
+}
+
+// DiskCache is a Cache that stores each result in a file under Dir, so that
+// the results survive a restart.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache creates a new DiskCache in dir, creating dir if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	d := new(DiskCache)
+	d.Dir = dir
+	return d, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	return filepath.Join(d.Dir, key[:2], key)
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	if len(key) < 2 {
+		return nil, false
+	}
+	value, err := ioutil.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Put writes the result to a temporary file and renames it into place, so a
+// concurrent Get never reads a partial result. Write errors are ignored; the
+// result is requested again on the next Get.
+func (d *DiskCache) Put(key string, value []byte) {
+	if len(key) < 2 {
+		return
+	}
+	p := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(filepath.Dir(p), key+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(value)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// TieredCache looks a result up in each of its Caches in turn, such as a
+// MemoryCache in front of a DiskCache. A result found in a later Cache is put
+// in the earlier ones, and new results are put in every Cache.
+type TieredCache []Cache
+
+func (t TieredCache) Get(key string) ([]byte, bool) {
+	for i, c := range t {
+		if value, ok := c.Get(key); ok {
+			for _, e := range t[:i] {
+				e.Put(key, value)
+			}
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (t TieredCache) Put(key string, value []byte) {
+	for _, c := range t {
+		c.Put(key, value)
+	}
+}
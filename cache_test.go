@@ -0,0 +1,171 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+func TestMemoryCacheEviction(t *testing.T) {
+	m := NewMemoryCache(10)
+	m.Put("aa", []byte("1234"))
+	m.Put("bb", []byte("1234"))
+	m.Get("aa")
+	// evicts bb, the least recently used
+	m.Put("cc", []byte("1234"))
+
+	if _, ok := m.Get("bb"); ok {
+		t.Error("expected bb to be evicted")
+	}
+	if _, ok := m.Get("aa"); !ok {
+		t.Error("expected aa to be kept")
+	}
+	if m.Len() != 2 || m.Size() != 8 {
+		t.Errorf("expected 2 results of 8 bytes, got %d of %d", m.Len(), m.Size())
+	}
+
+	m.Put("dd", []byte("12345678901"))
+	if _, ok := m.Get("dd"); ok {
+		t.Error("expected a result larger than the cache not to be kept")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("abcd", []byte("result"))
+
+	// a new cache in the same directory, as after a restart
+	d, _ = NewDiskCache(dir)
+	if v, ok := d.Get("abcd"); !ok || string(v) != "result" {
+		t.Errorf("expected the result from disk, got %q %v", v, ok)
+	}
+
+	m := NewMemoryCache(1024)
+	tc := TieredCache{m, d}
+	if v, ok := tc.Get("abcd"); !ok || string(v) != "result" {
+		t.Errorf("expected the result from the tiered cache, got %q %v", v, ok)
+	}
+	if _, ok := m.Get("abcd"); !ok {
+		t.Error("expected the result to be copied to the memory cache")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	requests := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(JSON2Request)
+		json.NewDecoder(r.Body).Decode(req)
+		requests[req.Method]++
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case "entry":
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"chainid":"0000000000000000000000000000000000000000000000000000000000000001","content":"6869","extids":[]}}`)
+		case "chain-head":
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"chainhead":"0000000000000000000000000000000000000000000000000000000000000000"}}`)
+		default:
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"error":{"code":-32008,"message":"Block not found"}}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.Cache = NewMemoryCache(1 << 20)
+	ctx := context.Background()
+	hash := "0000000000000000000000000000000000000000000000000000000000000002"
+	for i := 0; i < 3; i++ {
+		e, err := c.GetEntry(ctx, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(e.Content) != "hi" {
+			t.Errorf("expected content hi, got %q", e.Content)
+		}
+		c.GetChainHead(ctx, "0000000000000000000000000000000000000000000000000000000000000001")
+		c.GetDBlockByHeight(ctx, 1000000)
+	}
+
+	if requests["entry"] != 1 {
+		t.Errorf("expected the entry to be requested once, got %d", requests["entry"])
+	}
+	if requests["chain-head"] != 3 {
+		t.Errorf("expected the chain head to be requested every time, got %d", requests["chain-head"])
+	}
+	if requests["dblock-by-height"] != 3 {
+		t.Errorf("expected errors not to be cached, got %d requests", requests["dblock-by-height"])
+	}
+
+	// batches use the cache too
+	resps, err := c.SendBatch(ctx, []*JSON2Request{
+		NewJSON2Request("entry", APICounter(), struct {
+			Hash string `json:"hash"`
+		}{hash}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resps) != 1 || resps[0].Error != nil || requests["entry"] != 1 {
+		t.Errorf("expected the batch to be answered from the cache, got %v", resps)
+	}
+}
+
+func TestClientCacheNullAndMiddleware(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			// the block is not saved yet
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":null}`)
+			return
+		}
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"dblock":{}}}`)
+	}))
+	defer ts.Close()
+
+	var calls int
+	c := NewClient(ts.URL)
+	c.Cache = NewMemoryCache(1 << 20)
+	c.Middleware = []Middleware{func(next Handler) Handler {
+		return func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+			calls++
+			return next(ctx, req, header)
+		}
+	}}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		req := NewJSON2Request("dblock-by-height", APICounter(), struct {
+			Height int64 `json:"height"`
+		}{1000})
+		if _, err := c.SendRequest(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected the null result not to be cached, got %d requests", requests)
+	}
+	if calls != 3 {
+		t.Errorf("expected the cached request to pass through the Middleware, got %d calls", calls)
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Interaction is a request sent to an API server and the response it got, as
+// recorded in a cassette file. A cassette file holds one JSON encoded
+// Interaction per line.
+type Interaction struct {
+	// Method is the JSON RPC method of the request, or the methods of a
+	// batch separated by commas. It is informational only.
+	Method string `json:"method"`
+
+	// Path is the URL path of the API the request was sent to, such as /v2
+	// or /debug.
+	Path string `json:"path"`
+
+	Request  json.RawMessage `json:"request"`
+	Status   int             `json:"status"`
+	Response string          `json:"response"`
+}
+
+// Recorder is an http.RoundTripper that sends the requests of a Client or
+// WalletClient with the next RoundTripper and writes each request and its
+// response to a cassette.
+type Recorder struct {
+	next http.RoundTripper
+	w    io.Writer
+	lock sync.Mutex
+}
+
+// NewRecorder creates a new Recorder that writes the cassette to w. If next
+// is nil http.DefaultTransport is used.
+func NewRecorder(next http.RoundTripper, w io.Writer) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := new(Recorder)
+	r.next = next
+	r.w = w
+	return r
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	i := Interaction{
+		Method:   requestMethods(reqBody),
+		Path:     req.URL.Path,
+		Request:  reqBody,
+		Status:   resp.StatusCode,
+		Response: string(respBody),
+	}
+	line, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Cassette is an http.RoundTripper that answers the requests of a Client or
+// WalletClient from a recorded cassette without using the network. Requests
+// are matched on their URL path, methods and params, ignoring the request
+// IDs; the
+// recorded response is replayed with the ID of the new request. Each recorded
+// Interaction is replayed once, in the order they were recorded.
+type Cassette struct {
+	lock         sync.Mutex
+	interactions []Interaction
+	used         []bool
+	unmatched    []string
+}
+
+// NewCassette reads a cassette from r.
+func NewCassette(r io.Reader) (*Cassette, error) {
+	c := new(Cassette)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		i := Interaction{}
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %v", n, err)
+		}
+		c.interactions = append(c.interactions, i)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	c.used = make([]bool, len(c.interactions))
+
+	return c, nil
+}
+
+// OpenCassette reads a cassette from a file.
+func OpenCassette(path string) (*Cassette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewCassette(f)
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	key, err := interactionKey(req.URL.Path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for n, i := range c.interactions {
+		if c.used[n] {
+			continue
+		}
+		if k, err := interactionKey(i.Path, i.Request); err != nil || k != key {
+			continue
+		}
+		c.used[n] = true
+
+		body, err := replaceIDs([]byte(i.Response), i.Request, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+			StatusCode:    i.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	c.unmatched = append(c.unmatched, string(reqBody))
+	return nil, fmt.Errorf("cassette has no interaction for request %s", reqBody)
+}
+
+// Unmatched returns the requests that did not match any recorded Interaction.
+func (c *Cassette) Unmatched() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.unmatched...)
+}
+
+// Unused returns the recorded Interactions that were not replayed.
+func (c *Cassette) Unused() []Interaction {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	is := make([]Interaction, 0)
+	for n, i := range c.interactions {
+		if !c.used[n] {
+			is = append(is, i)
+		}
+	}
+	return is
+}
+
+// Check returns an error listing the unmatched requests and the unused
+// Interactions, or nil if every request was matched and every Interaction
+// replayed.
+func (c *Cassette) Check() error {
+	unmatched := c.Unmatched()
+	unused := c.Unused()
+	if len(unmatched) == 0 && len(unused) == 0 {
+		return nil
+	}
+
+	var s string
+	for _, r := range unmatched {
+		s += fmt.Sprintln("unmatched request:", r)
+	}
+	for _, i := range unused {
+		s += fmt.Sprintln("unused interaction:", string(i.Request))
+	}
+	return fmt.Errorf("cassette mismatch:\n%s", s)
+}
+
+// readBody reads and closes a request or response body.
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
+// requestMethods returns the methods of a request or a batch of requests.
+func requestMethods(body []byte) string {
+	reqs := make([]*JSON2Request, 0)
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		req := new(JSON2Request)
+		json.Unmarshal(body, req)
+		return req.Method
+	}
+	methods := make([]string, len(reqs))
+	for i, req := range reqs {
+		methods[i] = req.Method
+	}
+	return strings.Join(methods, ",")
+}
+
+// interactionKey returns the URL path and the canonical encoding of a request
+// or a batch of requests without their IDs.
+func interactionKey(path string, body []byte) (string, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "", err
+	}
+
+	// null params are the same as no params
+	strip := func(m map[string]interface{}) {
+		delete(m, "id")
+		if m["params"] == nil {
+			delete(m, "params")
+		}
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		strip(v)
+	case []interface{}:
+		for _, r := range v {
+			if m, ok := r.(map[string]interface{}); ok {
+				strip(m)
+			}
+		}
+	}
+
+	// maps are encoded with sorted keys
+	p, err := json.Marshal(v)
+	return path + " " + string(p), err
+}
+
+// replaceIDs rewrites the IDs of a recorded response to the IDs of the
+// requests being replayed. The response is returned unchanged if the IDs are
+// the same.
+func replaceIDs(resp, recorded, replayed []byte) ([]byte, error) {
+	oldIDs, err := requestIDs(recorded)
+	if err != nil {
+		return nil, err
+	}
+	newIDs, err := requestIDs(replayed)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]json.RawMessage)
+	same := true
+	for n := range oldIDs {
+		ids[string(oldIDs[n])] = newIDs[n]
+		same = same && bytes.Equal(oldIDs[n], newIDs[n])
+	}
+	if same {
+		return resp, nil
+	}
+
+	// splice the new IDs into the recorded bytes, leaving the rest of the
+	// response as it was recorded
+	locs, err := responseIDs(resp)
+	if err != nil {
+		// not a JSON RPC response, such as an error page from a proxy
+		return resp, nil
+	}
+	out := make([]byte, 0, len(resp))
+	last := 0
+	for _, l := range locs {
+		id, ok := ids[string(resp[l.start:l.end])]
+		if !ok {
+			continue
+		}
+		out = append(out, resp[last:l.start]...)
+		out = append(out, id...)
+		last = l.end
+	}
+	return append(out, resp[last:]...), nil
+}
+
+// idLocation is the offset of the encoded ID of a response.
+type idLocation struct {
+	start, end int
+}
+
+// responseIDs returns the offsets of the IDs of a response or a batch of
+// responses.
+func responseIDs(resp []byte) ([]idLocation, error) {
+	d := json.NewDecoder(bytes.NewReader(resp))
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == json.Delim('{') {
+		return objectIDs(d, resp, nil)
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("unexpected JSON token %v", tok)
+	}
+
+	var locs []idLocation
+	for d.More() {
+		if tok, err := d.Token(); err != nil {
+			return nil, err
+		} else if tok != json.Delim('{') {
+			return nil, fmt.Errorf("unexpected JSON token %v", tok)
+		}
+		if locs, err = objectIDs(d, resp, locs); err != nil {
+			return nil, err
+		}
+	}
+	return locs, nil
+}
+
+// objectIDs appends the offset of the "id" member of the object being decoded
+// by d to locs. The opening brace must already have been read.
+func objectIDs(d *json.Decoder, resp []byte, locs []idLocation) ([]idLocation, error) {
+	for d.More() {
+		key, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		start := int(d.InputOffset())
+		var v json.RawMessage
+		if err := d.Decode(&v); err != nil {
+			return nil, err
+		}
+		if key == "id" {
+			// the value follows the colon and any whitespace
+			end := int(d.InputOffset())
+			start += bytes.IndexFunc(resp[start:end], func(r rune) bool {
+				return !strings.ContainsRune(" \t\r\n:", r)
+			})
+			locs = append(locs, idLocation{start, end})
+		}
+	}
+	if _, err := d.Token(); err != nil {
+		return nil, err
+	}
+	return locs, nil
+}
+
+// requestIDs returns the encoded IDs of a request or a batch of requests.
+func requestIDs(body []byte) ([]json.RawMessage, error) {
+	type idRequest struct {
+		ID json.RawMessage `json:"id"`
+	}
+
+	if b := bytes.TrimSpace(body); len(b) > 0 && b[0] == '[' {
+		reqs := make([]idRequest, 0)
+		if err := json.Unmarshal(b, &reqs); err != nil {
+			return nil, err
+		}
+		ids := make([]json.RawMessage, len(reqs))
+		for i, r := range reqs {
+			ids[i] = r.ID
+		}
+		return ids, nil
+	}
+
+	r := idRequest{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+	return []json.RawMessage{r.ID}, nil
+}
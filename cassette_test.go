@@ -0,0 +1,203 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+// cassetteServer answers entry-credit-rate and heights, alone or in batches.
+func cassetteServer() *httptest.Server {
+	result := func(req *JSON2Request) *JSON2Response {
+		resp := NewJSON2Response()
+		resp.ID = req.ID
+		switch req.Method {
+		case "entry-credit-rate":
+			resp.Result = json.RawMessage(`{"rate":1000}`)
+		case "heights":
+			resp.Result = json.RawMessage(`{"directoryblockheight":42}`)
+		}
+		return resp
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(bytes.Buffer)
+		body.ReadFrom(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+
+		reqs := make([]*JSON2Request, 0)
+		if err := json.Unmarshal(body.Bytes(), &reqs); err == nil {
+			resps := make([]*JSON2Response, 0)
+			for _, req := range reqs {
+				resps = append(resps, result(req))
+			}
+			json.NewEncoder(w).Encode(resps)
+			return
+		}
+		req := new(JSON2Request)
+		json.Unmarshal(body.Bytes(), req)
+		json.NewEncoder(w).Encode(result(req))
+	}))
+}
+
+// cassetteCalls makes the calls recorded and replayed by the cassette tests.
+func cassetteCalls(t *testing.T, c *Client) {
+	ctx := context.Background()
+	if rate, err := c.GetECRate(ctx); err != nil {
+		t.Error(err)
+	} else if rate != 1000 {
+		t.Errorf("expected rate 1000, got %d", rate)
+	}
+	if h, err := c.GetHeights(ctx); err != nil {
+		t.Error(err)
+	} else if h.DirectoryBlockHeight != 42 {
+		t.Errorf("expected height 42, got %d", h.DirectoryBlockHeight)
+	}
+	resps, err := c.SendBatch(ctx, []*JSON2Request{
+		NewJSON2Request("heights", APICounter(), nil),
+		NewJSON2Request("entry-credit-rate", APICounter(), nil),
+	})
+	if err != nil {
+		t.Error(err)
+	} else if !strings.Contains(string(resps[1].Result), "1000") {
+		t.Errorf("batch responses out of order: %s", resps)
+	}
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	ts := cassetteServer()
+	cassette := new(bytes.Buffer)
+
+	c := NewClient(ts.URL)
+	c.Transport = NewRecorder(nil, cassette)
+	cassetteCalls(t, c)
+	ts.Close()
+
+	if n := strings.Count(cassette.String(), "\n"); n != 3 {
+		t.Fatalf("expected 3 recorded interactions, got %d:\n%s", n, cassette)
+	}
+
+	// replay with new request ids and without the server
+	cas, err := NewCassette(bytes.NewReader(cassette.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = NewClient(ts.URL)
+	c.Transport = cas
+	cassetteCalls(t, c)
+	if err := cas.Check(); err != nil {
+		t.Error(err)
+	}
+
+	// every interaction has been used
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Error("expected an error for a request missing from the cassette")
+	}
+	if len(cas.Unmatched()) != 1 || cas.Check() == nil {
+		t.Errorf("expected 1 unmatched request, got %v", cas.Unmatched())
+	}
+}
+
+func TestCassetteUnused(t *testing.T) {
+	line := `{"method":"%s","path":"/v2","request":{"jsonrpc":"2.0","id":7,"method":"%s"},"status":200,"response":"{\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{\"rate\":1000}}\n"}`
+	cas, err := NewCassette(strings.NewReader(
+		fmt.Sprintf(line, "entry-credit-rate", "entry-credit-rate") + "\n" +
+			fmt.Sprintf(line, "heights", "heights") + "\n",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient("localhost:1")
+	c.Transport = cas
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	unused := cas.Unused()
+	if len(unused) != 1 || unused[0].Method != "heights" {
+		t.Errorf("expected the heights interaction to be unused, got %v", unused)
+	}
+	if err := cas.Check(); err == nil || !strings.Contains(err.Error(), "unused interaction") {
+		t.Errorf("expected an unused interaction error, got %v", err)
+	}
+}
+
+func TestCassetteReplayBytes(t *testing.T) {
+	// the recorded responses have their keys out of order and extra whitespace
+	lines := []string{
+		`{"method":"entry-credit-rate","path":"/v2","request":{"jsonrpc":"2.0","id":7,"method":"entry-credit-rate"},"status":200,"response":"{\"result\": {\"rate\": 1000},\n \"jsonrpc\": \"2.0\",\n \"id\" : 7}\n"}`,
+		`{"method":"heights,entry-credit-rate","path":"/v2","request":[{"jsonrpc":"2.0","id":8,"method":"heights"},{"jsonrpc":"2.0","id":9,"method":"entry-credit-rate"}],"status":200,"response":"[{\"result\":{\"id\":8},\"id\":9,\"jsonrpc\":\"2.0\"}, {\"id\":8,\"result\":{}}]"}`,
+	}
+	cas, err := NewCassette(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay := func(body string) string {
+		req, err := http.NewRequest("POST", "http://localhost:1/v2", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cas.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		p, _ := ioutil.ReadAll(resp.Body)
+		return string(p)
+	}
+
+	got := replay(`{"jsonrpc":"2.0","id":42,"method":"entry-credit-rate"}`)
+	if want := "{\"result\": {\"rate\": 1000},\n \"jsonrpc\": \"2.0\",\n \"id\" : 42}\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	got = replay(`[{"jsonrpc":"2.0","id":43,"method":"heights"},{"jsonrpc":"2.0","id":44,"method":"entry-credit-rate"}]`)
+	if want := `[{"result":{"id":8},"id":44,"jsonrpc":"2.0"}, {"id":43,"result":{}}]`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCassettePath(t *testing.T) {
+	ts := cassetteServer()
+	defer ts.Close()
+	cassette := new(bytes.Buffer)
+
+	c := NewClient(ts.URL)
+	c.Transport = NewRecorder(nil, cassette)
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(cassette.String(), `"path":"/v2"`) {
+		t.Errorf("expected the /v2 path to be recorded, got %s", cassette)
+	}
+
+	// the same request sent to another API is not answered
+	debug := strings.Replace(cassette.String(), `"path":"/v2"`, `"path":"/debug"`, 1)
+	cas, err := NewCassette(strings.NewReader(debug))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Transport = cas
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Error("expected an error for a request recorded on another path")
+	}
+	if len(cas.Unmatched()) != 1 || len(cas.Unused()) != 1 {
+		t.Errorf("expected 1 unmatched request and 1 unused interaction, got %v and %v",
+			cas.Unmatched(), cas.Unused())
+	}
+}
@@ -6,10 +6,11 @@ package factom
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
+	"strings"
 )
 
 var (
@@ -51,6 +52,35 @@ func NewChainFromStrings(content string, extids ...string) *Chain {
 	return c
 }
 
+// Validate checks the Chain and its First Entry against the limits of the
+// Factom protocol. The returned ValidationError lists every violation found.
+func (c *Chain) Validate() error {
+	var v ValidationError
+	if err := validChainID(c.ChainID); err != nil {
+		v = append(v, &EntryError{"ChainID", err})
+	}
+
+	e := c.FirstEntry
+	if e == nil {
+		v = append(v, &EntryError{"FirstEntry", ErrNoFirstEntry})
+		return v
+	}
+	if !strings.EqualFold(e.ChainID, c.ChainID) {
+		v = append(v, &EntryError{"FirstEntry.ChainID", ErrChainIDMismatch})
+	}
+	if len(e.ExtIDs) == 0 {
+		v = append(v, &EntryError{"FirstEntry.ExtIDs", ErrChainNoExtIDs})
+	} else if !strings.EqualFold(ChainIDFromFields(e.ExtIDs), c.ChainID) {
+		v = append(v, &EntryError{"ChainID", ErrChainIDMismatch})
+	}
+	v = append(v, e.validatePayload("FirstEntry.")...)
+
+	if len(v) > 0 {
+		return v
+	}
+	return nil
+}
+
 // ChainIDFromFields computes a ChainID based on the binary External IDs of that
 // Chain's First Entry.
 func ChainIDFromFields(fields [][]byte) string {
@@ -75,8 +105,8 @@ func ChainIDFromStrings(fields []string) string {
 
 // ChainExists returns true if a Chain with the given chainid exists within the
 // Factom Blockchain.
-func ChainExists(chainid string) bool {
-	if _, _, err := GetChainHead(chainid); err == nil {
+func (c *Client) ChainExists(ctx context.Context, chainid string) bool {
+	if _, _, err := c.GetChainHead(ctx, chainid); err == nil {
 		// no error means we found the Chain
 		return true
 	}
@@ -85,8 +115,12 @@ func ChainExists(chainid string) bool {
 
 // ComposeChainCommit creates a JSON2Request to commit a new Chain via the
 // factomd web api. The request includes the marshaled MessageRequest with the
-// Entry Credit Signature.
+// Entry Credit Signature. The Chain is checked with Chain.Validate first.
 func ComposeChainCommit(c *Chain, ec *ECAddress) (*JSON2Request, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
 	buf := new(bytes.Buffer)
 
 	// 1 byte version
@@ -151,26 +185,19 @@ func ComposeChainReveal(c *Chain) (*JSON2Request, error) {
 // public key to the factom network. Once the payment is verified and the
 // network is commited to publishing the Chain it may be published by revealing
 // the First Entry in the Chain.
-func CommitChain(c *Chain, ec *ECAddress) (string, error) {
+func (c *Client) CommitChain(ctx context.Context, chain *Chain, ec *ECAddress) (string, error) {
 	type commitResponse struct {
 		Message string `json:"message"`
 		TxID    string `json:"txid"`
 	}
 
-	req, err := ComposeChainCommit(c, ec)
+	req, err := ComposeChainCommit(chain, ec)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != nil {
-		return "", resp.Error
-	}
 	r := new(commitResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := c.factomdCall(ctx, req, r); err != nil {
 		return "", err
 	}
 
@@ -179,27 +206,19 @@ func CommitChain(c *Chain, ec *ECAddress) (string, error) {
 
 // RevealChain sends the Chain data to the factom network to create a chain that
 // has previously been commited.
-func RevealChain(c *Chain) (string, error) {
+func (c *Client) RevealChain(ctx context.Context, chain *Chain) (string, error) {
 	type revealResponse struct {
 		Message string `json:"message"`
 		Entry   string `json:"entryhash"`
 	}
 
-	req, err := ComposeChainReveal(c)
+	req, err := ComposeChainReveal(chain)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != nil {
-		return "", resp.Error
-	}
-
 	r := new(revealResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := c.factomdCall(ctx, req, r); err != nil {
 		return "", err
 	}
 	return r.Entry, nil
@@ -207,22 +226,14 @@ func RevealChain(c *Chain) (string, error) {
 
 // GetChainHead returns the hash of the most recent Entry made into a given
 // Factom Chain.
-func GetChainHead(chainid string) (string, bool, error) {
+func (c *Client) GetChainHead(ctx context.Context, chainid string) (string, bool, error) {
 	params := chainIDRequest{ChainID: chainid}
 	req := NewJSON2Request("chain-head", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", false, err
-	}
-	if resp.Error != nil {
-		return "", false, resp.Error
-	}
-
 	head := new(struct {
 		ChainHead          string `json:"chainhead"`
 		ChainInProcessList bool   `json:"chaininprocesslist"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), head); err != nil {
+	if err := c.factomdCall(ctx, req, head); err != nil {
 		return "", false, err
 	}
 
@@ -230,10 +241,10 @@ func GetChainHead(chainid string) (string, bool, error) {
 }
 
 // GetAllChainEntries returns a list of all Factom Entries for a given Chain.
-func GetAllChainEntries(chainid string) ([]*Entry, error) {
+func (c *Client) GetAllChainEntries(ctx context.Context, chainid string) ([]*Entry, error) {
 	es := make([]*Entry, 0)
 
-	head, inPL, err := GetChainHead(chainid)
+	head, inPL, err := c.GetChainHead(ctx, chainid)
 	if err != nil {
 		return es, err
 	}
@@ -243,11 +254,15 @@ func GetAllChainEntries(chainid string) ([]*Entry, error) {
 	}
 
 	for ebhash := head; ebhash != ZeroHash; {
-		eb, err := GetEBlock(ebhash)
+		// stop walking the chain if the context is done
+		if err := ctx.Err(); err != nil {
+			return es, err
+		}
+		eb, err := c.GetEBlock(ctx, ebhash)
 		if err != nil {
 			return es, err
 		}
-		s, err := GetAllEBlockEntries(ebhash)
+		s, err := c.GetAllEBlockEntries(ctx, ebhash)
 		if err != nil {
 			return es, err
 		}
@@ -261,10 +276,10 @@ func GetAllChainEntries(chainid string) ([]*Entry, error) {
 
 // GetAllChainEntriesAtHeight returns a list of all Factom Entries for a given
 // Chain at a given point in the Chain's history.
-func GetAllChainEntriesAtHeight(chainid string, height int64) ([]*Entry, error) {
+func (c *Client) GetAllChainEntriesAtHeight(ctx context.Context, chainid string, height int64) ([]*Entry, error) {
 	es := make([]*Entry, 0)
 
-	head, inPL, err := GetChainHead(chainid)
+	head, inPL, err := c.GetChainHead(ctx, chainid)
 	if err != nil {
 		return es, err
 	}
@@ -274,7 +289,11 @@ func GetAllChainEntriesAtHeight(chainid string, height int64) ([]*Entry, error)
 	}
 
 	for ebhash := head; ebhash != ZeroHash; {
-		eb, err := GetEBlock(ebhash)
+		// stop walking the chain if the context is done
+		if err := ctx.Err(); err != nil {
+			return es, err
+		}
+		eb, err := c.GetEBlock(ctx, ebhash)
 		if err != nil {
 			return es, err
 		}
@@ -282,7 +301,7 @@ func GetAllChainEntriesAtHeight(chainid string, height int64) ([]*Entry, error)
 			ebhash = eb.Header.PrevKeyMR
 			continue
 		}
-		s, err := GetAllEBlockEntries(ebhash)
+		s, err := c.GetAllEBlockEntries(ctx, ebhash)
 		if err != nil {
 			return es, err
 		}
@@ -295,10 +314,10 @@ func GetAllChainEntriesAtHeight(chainid string, height int64) ([]*Entry, error)
 }
 
 // GetFirstEntry returns the first Entry used to create the given Factom Chain.
-func GetFirstEntry(chainid string) (*Entry, error) {
+func (c *Client) GetFirstEntry(ctx context.Context, chainid string) (*Entry, error) {
 	e := new(Entry)
 
-	head, inPL, err := GetChainHead(chainid)
+	head, inPL, err := c.GetChainHead(ctx, chainid)
 	if err != nil {
 		return e, err
 	}
@@ -307,18 +326,21 @@ func GetFirstEntry(chainid string) (*Entry, error) {
 		return nil, ErrChainPending
 	}
 
-	eb, err := GetEBlock(head)
+	eb, err := c.GetEBlock(ctx, head)
 	if err != nil {
 		return e, err
 	}
 
 	for eb.Header.PrevKeyMR != ZeroHash {
+		if err := ctx.Err(); err != nil {
+			return e, err
+		}
 		ebhash := eb.Header.PrevKeyMR
-		eb, err = GetEBlock(ebhash)
+		eb, err = c.GetEBlock(ctx, ebhash)
 		if err != nil {
 			return e, err
 		}
 	}
 
-	return GetEntry(eb.EntryList[0].EntryHash)
+	return c.GetEntry(ctx, eb.EntryList[0].EntryHash)
 }
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -110,6 +111,42 @@ func TestIfNotExists(t *testing.T) {
 	}
 }
 
+func TestChainValidate(t *testing.T) {
+	c := NewChainFromStrings("This is the first Entry.", "test", "chain")
+	if err := c.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	tests := []struct {
+		name  string
+		chain *Chain
+		err   error
+	}{
+		{"no first entry", &Chain{ChainID: c.ChainID}, ErrNoFirstEntry},
+		{"no extids", NewChainFromStrings("no extids"), ErrChainNoExtIDs},
+		{"bad chainid", &Chain{ChainID: "zz", FirstEntry: c.FirstEntry}, ErrChainIDFormat},
+		{"mismatched chainid", &Chain{
+			ChainID:    "5a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be709069",
+			FirstEntry: c.FirstEntry,
+		}, ErrChainIDMismatch},
+		{"too large", NewChainFromBytes(make([]byte, 10240), []byte("test")), ErrEntryTooLarge},
+	}
+	for _, test := range tests {
+		err := test.chain.Validate()
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+		if _, ok := err.(ValidationError); !ok {
+			t.Errorf("%s: expected a ValidationError, got %T", test.name, err)
+		}
+	}
+
+	ecAddr, _ := GetECAddress("Es2Rf7iM6PdsqfYCo3D1tnAR65SkLENyWJG1deUzpRMQmbh9F3eG")
+	if _, err := ComposeChainCommit(NewChainFromStrings("no extids"), ecAddr); !errors.Is(err, ErrChainNoExtIDs) {
+		t.Errorf("expected ComposeChainCommit to fail with %v, got %v", ErrChainNoExtIDs, err)
+	}
+}
+
 func TestComposeChainCommit(t *testing.T) {
 	type response struct {
 		Message string `json:"message"`
@@ -0,0 +1,383 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client is a factomd API client. Each Client holds its own connection
+// settings, so a single process may talk to any number of factomd servers at
+// once. Every factomd API call is available as a method on Client.
+//
+// A Client should not be modified once it is in use; it is then safe for
+// concurrent use by multiple goroutines.
+type Client struct {
+	Server      string
+	RPCUser     string
+	RPCPassword string
+	TLSEnable   bool
+	TLSCertFile string
+	Timeout     time.Duration
+
+	// TLS, if set, enables TLS with its settings, such as extra CAs, a client
+	// certificate for mutual TLS and pinned server keys. TLSCertFile, if TLS
+	// is also enabled by TLSEnable, is trusted as an extra CA.
+	TLS *TLSConfig
+
+	// Jar accepts and manages cookies from the factomd server. Cookies are
+	// ignored if Jar is nil.
+	Jar http.CookieJar
+
+	// Retry is the policy for retrying requests that fail with a transient
+	// error. Requests are not retried if Retry is nil.
+	Retry *RetryPolicy
+
+	// Pool is a set of factomd servers that requests are sent to instead of
+	// Server. WritePool, if set, is used instead of Pool for the requests
+	// that change the state of the network, such as commit-entry.
+	Pool      *ServerPool
+	WritePool *ServerPool
+
+	// HTTPClient, if set, is used to send the requests instead of an
+	// http.Client built from the settings above. Transport, if set, is used
+	// instead of the shared transport built from TransportConfig and the TLS
+	// settings.
+	HTTPClient      *http.Client
+	Transport       http.RoundTripper
+	TransportConfig TransportConfig
+
+	// Middleware wraps every request sent to the API server, the first
+	// Middleware being the outermost.
+	Middleware []Middleware
+
+	// Cache, if set, holds the results of the requests for data that never
+	// changes, such as Entries and blocks, so they are only requested once.
+	// The requests answered from the Cache still pass through the Middleware.
+	Cache Cache
+
+	// Limiter, if set, limits the rate of the requests sent to the API
+	// server and pauses them when the server answers 429 Too Many Requests.
+	Limiter *RateLimiter
+
+	// MaxResponseSize is the largest response body in bytes that is read
+	// from the API server. Larger responses fail with ErrResponseTooLarge.
+	// It is DefaultMaxResponseSize if 0; a negative MaxResponseSize does not
+	// limit the responses.
+	MaxResponseSize int64
+}
+
+// NewClient creates a new factomd API Client for the given server.
+func NewClient(server string) *Client {
+	c := new(Client)
+	c.Server = server
+	c.MaxResponseSize = DefaultMaxResponseSize
+	return c
+}
+
+// NewClientFromConfig creates a new factomd API Client from the factomd
+// settings of an RPCConfig.
+func NewClientFromConfig(cfg *RPCConfig) *Client {
+	c := new(Client)
+	c.Server = cfg.FactomdServer
+	c.RPCUser = cfg.FactomdRPCUser
+	c.RPCPassword = cfg.FactomdRPCPassword
+	c.TLSEnable = cfg.FactomdTLSEnable
+	c.TLSCertFile = cfg.FactomdTLSCertFile
+	c.TLS = cfg.FactomdTLS
+	c.Timeout = cfg.FactomdTimeout
+	c.Retry = cfg.FactomdRetry
+	c.Cache = cfg.FactomdCache
+	c.Limiter = cfg.FactomdLimiter
+	c.MaxResponseSize = cfg.FactomdMaxResponseSize
+	return c
+}
+
+// WalletClient is a factom-walletd API client. Each WalletClient holds its own
+// connection settings and every factom-walletd API call is available as a
+// method on WalletClient.
+//
+// A WalletClient should not be modified once it is in use; it is then safe for
+// concurrent use by multiple goroutines.
+type WalletClient struct {
+	Server      string
+	RPCUser     string
+	RPCPassword string
+	TLSEnable   bool
+	TLSCertFile string
+	Timeout     time.Duration
+
+	// TLS, if set, enables TLS with its settings, as for Client.
+	TLS *TLSConfig
+
+	// HTTPClient, if set, is used to send the requests instead of an
+	// http.Client built from the settings above. Transport, if set, is used
+	// instead of the shared transport built from TransportConfig and the TLS
+	// settings.
+	HTTPClient      *http.Client
+	Transport       http.RoundTripper
+	TransportConfig TransportConfig
+
+	// Middleware wraps every request sent to the API server, the first
+	// Middleware being the outermost.
+	Middleware []Middleware
+
+	// Limiter, if set, limits the rate of the requests sent to the API
+	// server and pauses them when the server answers 429 Too Many Requests.
+	Limiter *RateLimiter
+
+	// MaxResponseSize is the largest response body in bytes that is read
+	// from the API server, as for Client.
+	MaxResponseSize int64
+
+	// Factomd is the factomd Client used by the wallet calls that also need
+	// the factomd API, such as SendTransaction.
+	Factomd *Client
+}
+
+// NewWalletClient creates a new factom-walletd API WalletClient for the given
+// server. The factomd Client is used for the calls that also need factomd.
+func NewWalletClient(server string, factomd *Client) *WalletClient {
+	w := new(WalletClient)
+	w.Server = server
+	w.Factomd = factomd
+	w.MaxResponseSize = DefaultMaxResponseSize
+	return w
+}
+
+// NewWalletClientFromConfig creates a new factom-walletd API WalletClient
+// from an RPCConfig. The wallet's factomd Client is created from the same
+// RPCConfig.
+func NewWalletClientFromConfig(cfg *RPCConfig) *WalletClient {
+	w := new(WalletClient)
+	w.Server = cfg.WalletServer
+	w.RPCUser = cfg.WalletRPCUser
+	w.RPCPassword = cfg.WalletRPCPassword
+	w.TLSEnable = cfg.WalletTLSEnable
+	w.TLSCertFile = cfg.WalletTLSCertFile
+	w.TLS = cfg.WalletTLS
+	w.Timeout = cfg.WalletTimeout
+	w.Limiter = cfg.WalletLimiter
+	w.MaxResponseSize = cfg.WalletMaxResponseSize
+	w.Factomd = NewClientFromConfig(cfg)
+	return w
+}
+
+// withServer returns a copy of the Client that sends its requests to a single
+// server without retries.
+func (c *Client) withServer(server string) *Client {
+	s := new(Client)
+	*s = *c
+	s.Server = server
+	s.Retry = nil
+	s.Pool = nil
+	s.WritePool = nil
+	return s
+}
+
+// factomdClient returns a Client using the package level RpcConfig. It is
+// used by the package level API functions.
+func factomdClient() *Client {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
+
+	c := NewClientFromConfig(RpcConfig)
+	c.Jar = cookieJar
+	return c
+}
+
+// walletClient returns a WalletClient using the package level RpcConfig. It is
+// used by the package level API functions.
+func walletClient() *WalletClient {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
+
+	w := NewWalletClientFromConfig(RpcConfig)
+	w.Factomd.Jar = cookieJar
+	return w
+}
+
+// SendRequest sends a json object to factomd
+func (c *Client) SendRequest(ctx context.Context, req *JSON2Request) (*JSON2Response, error) {
+	return c.factomdRequest(ctx, req)
+}
+
+// SendRequest sends a json object to factom-walletd
+func (w *WalletClient) SendRequest(ctx context.Context, req *JSON2Request) (*JSON2Response, error) {
+	return w.walletRequest(ctx, req)
+}
+
+// The paths of the JSON RPC APIs of factomd and factom-walletd, and of the
+// factomd debug API.
+const (
+	apiPath   = "/v2"
+	debugPath = "/debug"
+)
+
+// factomdRequest sends a JSON RPC request to the factomd API server through
+// the Client's Middleware and returns the corresponding API response. The
+// response is taken from the Client's Cache if possible.
+func (c *Client) factomdRequest(ctx context.Context, req *JSON2Request) (*JSON2Response, error) {
+	h := chainMiddleware(c.middleware(), c.factomdHandler(apiPath))
+	return h(ctx, req, make(http.Header))
+}
+
+// debugRequest sends a JSON RPC request to the factomd debug API through the
+// Client's Middleware and returns the corresponding API response.
+func (c *Client) debugRequest(ctx context.Context, req *JSON2Request) (*JSON2Response, error) {
+	h := chainMiddleware(c.Middleware, c.factomdHandler(debugPath))
+	return h(ctx, req, make(http.Header))
+}
+
+// factomdHandler returns the Handler that sends a JSON RPC request to the API
+// of the factomd server at the given path.
+func (c *Client) factomdHandler(path string) Handler {
+	return func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+		j, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+
+		r := NewJSON2Response()
+		err = c.factomdSend(ctx, path, IsIdempotentMethod(req.Method), header, j, responseDecoder(ctx, req, r))
+		if err != nil {
+			return nil, err
+		}
+
+		return r, nil
+	}
+}
+
+// factomdSend posts a JSON encoded request body to the API at path of the
+// factomd server, or of the servers of the Client's pool, retrying under the
+// Client's RetryPolicy. The body of the response is read by decode.
+func (c *Client) factomdSend(
+	ctx context.Context,
+	path string,
+	idempotent bool,
+	header http.Header,
+	j []byte,
+	decode func(io.Reader) error,
+) error {
+	return c.Retry.retry(ctx, idempotent, func() error {
+		pool := c.Pool
+		if !idempotent && c.WritePool != nil {
+			pool = c.WritePool
+		}
+		if pool == nil {
+			return c.factomdPost(ctx, c.Server, path, header, j, decode)
+		}
+		return pool.post(ctx, c, path, idempotent, header, j, decode)
+	})
+}
+
+// factomdPost posts a JSON encoded request body with the given HTTP headers to
+// the API at path of a factomd server and reads the body of the response with
+// decode.
+func (c *Client) factomdPost(
+	ctx context.Context,
+	server string,
+	path string,
+	header http.Header,
+	j []byte,
+	decode func(io.Reader) error,
+) error {
+	url, err := serverURL(server, c.TLSEnable || c.TLS != nil, path)
+	if err != nil {
+		return err
+	}
+	client, err := c.httpClient()
+	if err != nil {
+		return err
+	}
+
+	re, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
+	if err != nil {
+		return err
+	}
+
+	setHeader(re, header, c.RPCUser, c.RPCPassword)
+	err = c.Limiter.do(ctx, client, re, func(resp *http.Response) error {
+		if resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("Factomd username/password incorrect.  Edit factomd.conf or\ncall factom-cli with -factomduser=<user> -factomdpassword=<pass>")
+		}
+		return readResponse(resp, c.MaxResponseSize, decode)
+	})
+	if err != nil {
+		errs := fmt.Sprintf("%s", err)
+		if strings.Contains(errs, "\\x15\\x03\\x01\\x00\\x02\\x02\\x16") {
+			err = fmt.Errorf("Factomd API connection is encrypted. Please specify -factomdtls=true and -factomdcert=factomdAPIpub.cert (%w)", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// walletRequest sends a JSON RPC request to the factom wallet API server
+// through the WalletClient's Middleware and returns the corresponding API
+// response.
+func (w *WalletClient) walletRequest(ctx context.Context, req *JSON2Request) (*JSON2Response, error) {
+	h := chainMiddleware(w.Middleware, w.walletHandler)
+	return h(ctx, req, make(http.Header))
+}
+
+// walletHandler is the Handler that sends a JSON RPC request to the factom
+// wallet API server.
+func (w *WalletClient) walletHandler(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+	j, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewJSON2Response()
+	if err := w.walletPost(ctx, header, j, responseDecoder(ctx, req, r)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// walletPost posts a JSON encoded request body with the given HTTP headers to
+// the factom wallet API server and reads the body of the response with decode.
+func (w *WalletClient) walletPost(ctx context.Context, header http.Header, j []byte, decode func(io.Reader) error) error {
+	url, err := serverURL(w.Server, w.TLSEnable || w.TLS != nil, apiPath)
+	if err != nil {
+		return err
+	}
+	client, err := w.httpClient()
+	if err != nil {
+		return err
+	}
+
+	re, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
+	if err != nil {
+		return err
+	}
+
+	setHeader(re, header, w.RPCUser, w.RPCPassword)
+	err = w.Limiter.do(ctx, client, re, func(resp *http.Response) error {
+		if resp.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("Wallet username/password incorrect.  Edit factomd.conf or\ncall factom-cli with -walletuser=<user> -walletpassword=<pass>")
+		}
+		return readResponse(resp, w.MaxResponseSize, decode)
+	})
+	if err != nil {
+		errs := fmt.Sprintf("%s", err)
+		if strings.Contains(errs, "\\x15\\x03\\x01\\x00\\x02\\x02\\x16") {
+			err = fmt.Errorf("Factom-walletd API connection is encrypted. Please specify -wallettls=true and -walletcert=walletAPIpub.cert (%w)", err)
+		}
+		return err
+	}
+
+	return nil
+}
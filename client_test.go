@@ -0,0 +1,159 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+func TestClientsAreIndependent(t *testing.T) {
+	newServer := func(height int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":0,"result":{"directoryblockheight":%d,"leaderheight":%d,"entryblockheight":%d,"entryheight":%d}}`, height, height, height, height)
+		}))
+	}
+	ts1 := newServer(100)
+	defer ts1.Close()
+	ts2 := newServer(200)
+	defer ts2.Close()
+
+	c1 := NewClient(ts1.URL)
+	c2 := NewClient(ts2.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			h, err := c1.GetHeights(context.Background())
+			if err != nil {
+				t.Error(err)
+			} else if h.DirectoryBlockHeight != 100 {
+				t.Errorf("expected height 100, got %d", h.DirectoryBlockHeight)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			h, err := c2.GetHeights(context.Background())
+			if err != nil {
+				t.Error(err)
+			} else if h.DirectoryBlockHeight != 200 {
+				t.Errorf("expected height 200, got %d", h.DirectoryBlockHeight)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClientAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"rate":1000}}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Error("expected an error without credentials")
+	}
+
+	c.RPCUser = "user"
+	c.RPCPassword = "pass"
+	rate, err := c.GetECRate(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if rate != 1000 {
+		t.Errorf("expected rate 1000, got %d", rate)
+	}
+}
+
+func TestNewWalletClientFromConfig(t *testing.T) {
+	cfg := &RPCConfig{
+		WalletServer:       "localhost:8089",
+		WalletRPCUser:      "wuser",
+		WalletTimeout:      time.Second,
+		FactomdServer:      "localhost:8088",
+		FactomdRPCPassword: "fpass",
+	}
+
+	w := NewWalletClientFromConfig(cfg)
+	if w.Server != "localhost:8089" || w.RPCUser != "wuser" || w.Timeout != time.Second {
+		t.Errorf("wallet settings not copied from config: %+v", w)
+	}
+	if w.Factomd == nil {
+		t.Fatal("wallet client has no factomd client")
+	}
+	if w.Factomd.Server != "localhost:8088" || w.Factomd.RPCPassword != "fpass" {
+		t.Errorf("factomd settings not copied from config: %+v", w.Factomd)
+	}
+}
+
+func TestClientContextDeadline(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// never answer before the client gives up
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	c := NewClient(ts.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetHeights(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClientContextCancelsChainWalk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		req := new(JSON2Request)
+		json.NewDecoder(r.Body).Decode(req)
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case "chain-head":
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"chainhead":"0000000000000000000000000000000000000000000000000000000000000002","chaininprocesslist":false}}`)
+		case "entry-block":
+			// cancel the walk after the first Entry Block is returned
+			cancel()
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"header":{"prevkeymr":"0000000000000000000000000000000000000000000000000000000000000001"},"entrylist":[]}}`)
+		default:
+			t.Errorf("unexpected request %s", req.Method)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	if _, err := c.GetAllChainEntries(ctx, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the walk to stop after 2 requests, made %d", requests)
+	}
+}
@@ -0,0 +1,438 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig is returned when a configuration file, an environment
+// variable or an RPCConfig holds an invalid setting.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// DefaultConfigPath returns the path of the factomd.conf file used by
+// factomd, factom-walletd and factom-cli, ~/.factom/m2/factomd.conf.
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".factom", "m2", "factomd.conf")
+	}
+	return filepath.Join(home, ".factom", "m2", "factomd.conf")
+}
+
+// NewRPCConfig returns an RPCConfig with the default settings.
+func NewRPCConfig() *RPCConfig {
+	cfg := new(RPCConfig)
+	cfg.FactomdServer = "localhost:8088"
+	cfg.WalletServer = "localhost:8089"
+	return cfg
+}
+
+// LoadConfig builds an RPCConfig, applying the settings in order of
+// precedence, lowest first:
+//
+//  1. the defaults of NewRPCConfig
+//  2. the configuration files, in the order given; if no files are given
+//     DefaultConfigPath is used if it exists. Within a file the [Walletd]
+//     section takes precedence over the [app] section, so FactomdLocation
+//     overrides PortNumber
+//  3. the FACTOMD_* and FACTOM_WALLET_* environment variables
+//
+// The resulting RPCConfig is validated before it is returned.
+func LoadConfig(files ...string) (*RPCConfig, error) {
+	cfg := NewRPCConfig()
+
+	if len(files) == 0 {
+		if _, err := os.Stat(DefaultConfigPath()); err == nil {
+			files = []string{DefaultConfigPath()}
+		}
+	}
+	for _, file := range files {
+		if err := cfg.LoadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.LoadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// configSections are the sections of a configuration file that concern the
+// API clients, in the order they are applied. The [Walletd] section is
+// applied last, so its FactomdLocation takes precedence over the PortNumber
+// of the [app] section wherever they are in the file.
+var configSections = []string{"app", "walletd"}
+
+// configKeys maps the settings of the [app] section of factomd.conf and the
+// [Walletd] section shared by factom-walletd and factom-cli to the fields of
+// an RPCConfig. The keys are lower case.
+var configKeys = map[string]map[string]func(cfg *RPCConfig, v string) error{
+	"app": {
+		"portnumber": func(cfg *RPCConfig, v string) error {
+			if _, err := strconv.ParseUint(v, 10, 16); err != nil {
+				return err
+			}
+			cfg.FactomdServer = "localhost:" + v
+			return nil
+		},
+		"factomdtlsenabled":    setBool(func(cfg *RPCConfig) *bool { return &cfg.FactomdTLSEnable }),
+		"factomdtlspubliccert": setPath(func(cfg *RPCConfig) *string { return &cfg.FactomdTLSCertFile }),
+		"factomdrpcuser":       setString(func(cfg *RPCConfig) *string { return &cfg.FactomdRPCUser }),
+		"factomdrpcpass":       setString(func(cfg *RPCConfig) *string { return &cfg.FactomdRPCPassword }),
+	},
+	"walletd": {
+		"walletrpcuser":       setString(func(cfg *RPCConfig) *string { return &cfg.WalletRPCUser }),
+		"walletrpcpass":       setString(func(cfg *RPCConfig) *string { return &cfg.WalletRPCPassword }),
+		"wallettlsenabled":    setBool(func(cfg *RPCConfig) *bool { return &cfg.WalletTLSEnable }),
+		"wallettlsprivatekey": setPath(func(cfg *RPCConfig) *string { return &cfg.WalletTLSKeyFile }),
+		"wallettlspubliccert": setPath(func(cfg *RPCConfig) *string { return &cfg.WalletTLSCertFile }),
+		"factomdlocation":     setString(func(cfg *RPCConfig) *string { return &cfg.FactomdServer }),
+		"walletdlocation":     setString(func(cfg *RPCConfig) *string { return &cfg.WalletServer }),
+	},
+}
+
+// configEnv maps the environment variables to the fields of an RPCConfig.
+var configEnv = map[string]func(cfg *RPCConfig, v string) error{
+	"FACTOMD_SERVER":             setString(func(cfg *RPCConfig) *string { return &cfg.FactomdServer }),
+	"FACTOMD_RPC_USER":           setString(func(cfg *RPCConfig) *string { return &cfg.FactomdRPCUser }),
+	"FACTOMD_RPC_PASSWORD":       setString(func(cfg *RPCConfig) *string { return &cfg.FactomdRPCPassword }),
+	"FACTOMD_TLS_ENABLE":         setBool(func(cfg *RPCConfig) *bool { return &cfg.FactomdTLSEnable }),
+	"FACTOMD_TLS_CERT":           setPath(func(cfg *RPCConfig) *string { return &cfg.FactomdTLSCertFile }),
+	"FACTOMD_TIMEOUT":            setDuration(func(cfg *RPCConfig) *time.Duration { return &cfg.FactomdTimeout }),
+	"FACTOM_WALLET_SERVER":       setString(func(cfg *RPCConfig) *string { return &cfg.WalletServer }),
+	"FACTOM_WALLET_RPC_USER":     setString(func(cfg *RPCConfig) *string { return &cfg.WalletRPCUser }),
+	"FACTOM_WALLET_RPC_PASSWORD": setString(func(cfg *RPCConfig) *string { return &cfg.WalletRPCPassword }),
+	"FACTOM_WALLET_TLS_ENABLE":   setBool(func(cfg *RPCConfig) *bool { return &cfg.WalletTLSEnable }),
+	"FACTOM_WALLET_TLS_CERT":     setPath(func(cfg *RPCConfig) *string { return &cfg.WalletTLSCertFile }),
+	"FACTOM_WALLET_TLS_KEY":      setPath(func(cfg *RPCConfig) *string { return &cfg.WalletTLSKeyFile }),
+	"FACTOM_WALLET_TIMEOUT":      setDuration(func(cfg *RPCConfig) *time.Duration { return &cfg.WalletTimeout }),
+
+	// the TLSConfig settings; lists are comma separated
+	"FACTOMD_TLS_CA":                setTLS(factomdTLS, tlsCAFiles),
+	"FACTOMD_TLS_CLIENT_CERT":       setTLS(factomdTLS, tlsClientCert),
+	"FACTOMD_TLS_CLIENT_KEY":        setTLS(factomdTLS, tlsClientKey),
+	"FACTOMD_TLS_SERVER_NAME":       setTLS(factomdTLS, tlsServerName),
+	"FACTOMD_TLS_MIN_VERSION":       setTLS(factomdTLS, tlsMinVersion),
+	"FACTOMD_TLS_PIN":               setTLS(factomdTLS, tlsPins),
+	"FACTOM_WALLET_TLS_CA":          setTLS(walletTLS, tlsCAFiles),
+	"FACTOM_WALLET_TLS_CLIENT_CERT": setTLS(walletTLS, tlsClientCert),
+	"FACTOM_WALLET_TLS_CLIENT_KEY":  setTLS(walletTLS, tlsClientKey),
+	"FACTOM_WALLET_TLS_SERVER_NAME": setTLS(walletTLS, tlsServerName),
+	"FACTOM_WALLET_TLS_MIN_VERSION": setTLS(walletTLS, tlsMinVersion),
+	"FACTOM_WALLET_TLS_PIN":         setTLS(walletTLS, tlsPins),
+}
+
+func setString(field func(*RPCConfig) *string) func(*RPCConfig, string) error {
+	return func(cfg *RPCConfig, v string) error {
+		*field(cfg) = v
+		return nil
+	}
+}
+
+// setPath sets a file path, expanding a leading ~ to the home directory.
+func setPath(field func(*RPCConfig) *string) func(*RPCConfig, string) error {
+	return func(cfg *RPCConfig, v string) error {
+		p, err := expandPath(v)
+		if err != nil {
+			return err
+		}
+		*field(cfg) = p
+		return nil
+	}
+}
+
+// expandPath expands a leading ~ of a file path to the home directory.
+func expandPath(v string) (string, error) {
+	if v == "~" || strings.HasPrefix(v, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		v = filepath.Join(home, v[1:])
+	}
+	return v, nil
+}
+
+func setBool(field func(*RPCConfig) *bool) func(*RPCConfig, string) error {
+	return func(cfg *RPCConfig, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*field(cfg) = b
+		return nil
+	}
+}
+
+// setDuration sets a duration given as a Go duration, such as 10s, or as a
+// number of seconds.
+func setDuration(field func(*RPCConfig) *time.Duration) func(*RPCConfig, string) error {
+	return func(cfg *RPCConfig, v string) error {
+		if s, err := strconv.Atoi(v); err == nil {
+			*field(cfg) = time.Duration(s) * time.Second
+			return nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(cfg) = d
+		return nil
+	}
+}
+
+func factomdTLS(cfg *RPCConfig) **TLSConfig { return &cfg.FactomdTLS }
+func walletTLS(cfg *RPCConfig) **TLSConfig  { return &cfg.WalletTLS }
+
+// setTLS sets a field of a TLSConfig, creating the TLSConfig if needed.
+func setTLS(field func(*RPCConfig) **TLSConfig, set func(*TLSConfig, string) error) func(*RPCConfig, string) error {
+	return func(cfg *RPCConfig, v string) error {
+		t := field(cfg)
+		if *t == nil {
+			*t = new(TLSConfig)
+		}
+		return set(*t, v)
+	}
+}
+
+func tlsCAFiles(t *TLSConfig, v string) error {
+	var files []string
+	for _, f := range splitList(v) {
+		p, err := expandPath(f)
+		if err != nil {
+			return err
+		}
+		files = append(files, p)
+	}
+	t.CAFiles = files
+	return nil
+}
+
+func tlsClientCert(t *TLSConfig, v string) (err error) {
+	t.ClientCertFile, err = expandPath(v)
+	return err
+}
+
+func tlsClientKey(t *TLSConfig, v string) (err error) {
+	t.ClientKeyFile, err = expandPath(v)
+	return err
+}
+
+func tlsServerName(t *TLSConfig, v string) error {
+	t.ServerName = v
+	return nil
+}
+
+// tlsMinVersion sets the minimum TLS version given as 1.0, 1.1, 1.2 or 1.3.
+func tlsMinVersion(t *TLSConfig, v string) error {
+	versions := map[string]uint16{
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+	version, ok := versions[v]
+	if !ok {
+		return fmt.Errorf("unknown TLS version %q", v)
+	}
+	t.MinVersion = version
+	return nil
+}
+
+func tlsPins(t *TLSConfig, v string) error {
+	t.PinnedSPKI = splitList(v)
+	return nil
+}
+
+// splitList splits a comma separated list, dropping the empty elements.
+func splitList(v string) []string {
+	var list []string
+	for _, e := range strings.Split(v, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// LoadFile applies the settings of a factomd.conf style configuration file to
+// the RPCConfig. Settings missing from the file are left unchanged, and
+// sections and settings that do not concern the API clients are ignored.
+func (cfg *RPCConfig) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := cfg.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Load applies the settings of a factomd.conf style configuration read from
+// r to the RPCConfig. The settings of the [Walletd] section take precedence
+// over those of the [app] section.
+func (cfg *RPCConfig) Load(r io.Reader) error {
+	type setting struct {
+		line  int
+		key   string
+		value string
+		set   func(*RPCConfig, string) error
+	}
+	settings := make(map[string][]setting)
+
+	var section string
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end == -1 {
+				return fmt.Errorf("line %d: %w: unterminated section %q", n, ErrInvalidConfig, line)
+			}
+			section = strings.ToLower(strings.TrimSpace(line[1:end]))
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i == -1 {
+			return fmt.Errorf("line %d: %w: expected key = value, got %q", n, ErrInvalidConfig, line)
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		value := configValue(line[i+1:])
+
+		set, ok := configKeys[section][key]
+		if !ok {
+			continue
+		}
+		settings[section] = append(settings[section], setting{n, key, value, set})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for _, section := range configSections {
+		for _, s := range settings[section] {
+			if err := s.set(cfg, s.value); err != nil {
+				return fmt.Errorf("line %d: %w: %s: %v", s.line, ErrInvalidConfig, s.key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configValue returns a configuration value without its quotes or trailing
+// comment.
+func configValue(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) > 0 && v[0] == '"' {
+		if end := strings.IndexByte(v[1:], '"'); end != -1 {
+			return v[1 : end+1]
+		}
+	}
+	if i := strings.IndexAny(v, ";#"); i != -1 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
+
+// LoadEnv applies the settings of the FACTOMD_* and FACTOM_WALLET_* environment
+// variables to the RPCConfig. Variables that are not set are ignored.
+func (cfg *RPCConfig) LoadEnv() error {
+	for name, set := range configEnv {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := set(cfg, v); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, name, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the RPCConfig, including that the TLS certificate files of
+// the servers with TLS enabled can be read, and returns an error describing
+// every problem found.
+func (cfg *RPCConfig) Validate() error {
+	var problems []string
+
+	if cfg.FactomdServer == "" {
+		problems = append(problems, "factomd server is not set")
+	}
+	if cfg.WalletServer == "" {
+		problems = append(problems, "wallet server is not set")
+	}
+	if cfg.FactomdTimeout < 0 {
+		problems = append(problems, "factomd timeout is negative")
+	}
+	if cfg.WalletTimeout < 0 {
+		problems = append(problems, "wallet timeout is negative")
+	}
+	if cfg.FactomdTLSEnable {
+		if err := checkFile(cfg.FactomdTLSCertFile); err != nil {
+			problems = append(problems, "factomd TLS certificate: "+err.Error())
+		}
+	}
+	if cfg.WalletTLSEnable {
+		if err := checkFile(cfg.WalletTLSCertFile); err != nil {
+			problems = append(problems, "wallet TLS certificate: "+err.Error())
+		}
+	}
+	if cfg.FactomdTLS != nil {
+		if err := cfg.FactomdTLS.Validate(); err != nil {
+			problems = append(problems, "factomd TLS: "+err.Error())
+		}
+	}
+	if cfg.WalletTLS != nil {
+		if err := cfg.WalletTLS.Validate(); err != nil {
+			problems = append(problems, "wallet TLS: "+err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkFile returns an error if the file cannot be read.
+func checkFile(path string) error {
+	if path == "" {
+		return errors.New("file is not set")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if fi, err := f.Stat(); err != nil {
+		return err
+	} else if fi.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+const testFactomdConf = `; ------------------------------------------------------------------------------
+; App settings
+; ------------------------------------------------------------------------------
+[app]
+PortNumber                            = 8188
+HomeDir                               = ""
+ControlPanelPort                      = 8090
+FactomdTlsEnabled                     = false
+FactomdTlsPrivateKey                  = "/full/path/to/factomdAPIpriv.key"
+FactomdTlsPublicCert                  = "/full/path/to/factomdAPIpub.cert"
+FactomdRpcUser                        = "fuser"
+FactomdRpcPass                        = "fpass" ; a comment
+
+[Walletd]
+; These are the username and password that factom-walletd requires
+WalletRpcUser                         = "wuser"
+WalletRpcPass                         = "wpass"
+WalletTlsEnabled                      = false
+WalletdLocation                       = "localhost:8189"
+WalletEncrypted                       = false
+`
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	conf := writeConfig(t, dir, "factomd.conf", testFactomdConf)
+	cli := writeConfig(t, dir, "cli.conf", "[walletd]\nwalletrpcuser = cliuser\n")
+
+	os.Setenv("FACTOM_WALLET_RPC_PASSWORD", "envpass")
+	os.Setenv("FACTOMD_TIMEOUT", "15")
+	defer os.Unsetenv("FACTOM_WALLET_RPC_PASSWORD")
+	defer os.Unsetenv("FACTOMD_TIMEOUT")
+
+	cfg, err := LoadConfig(conf, cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.FactomdServer != "localhost:8188" || cfg.WalletServer != "localhost:8189" {
+		t.Errorf("servers not loaded: %s %s", cfg.FactomdServer, cfg.WalletServer)
+	}
+	if cfg.FactomdRPCUser != "fuser" || cfg.FactomdRPCPassword != "fpass" {
+		t.Errorf("factomd credentials not loaded: %s %s", cfg.FactomdRPCUser, cfg.FactomdRPCPassword)
+	}
+	// the later file overrides the first, and the environment both
+	if cfg.WalletRPCUser != "cliuser" || cfg.WalletRPCPassword != "envpass" {
+		t.Errorf("wallet credentials not overridden: %s %s", cfg.WalletRPCUser, cfg.WalletRPCPassword)
+	}
+	if cfg.FactomdTimeout != 15*time.Second {
+		t.Errorf("expected timeout 15s, got %v", cfg.FactomdTimeout)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bad := writeConfig(t, dir, "bad.conf", "[app]\nFactomdTlsEnabled = maybe\n")
+	if _, err := LoadConfig(bad); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a bad boolean, got %v", err)
+	}
+
+	if _, err := LoadConfig(filepath.Join(dir, "missing.conf")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a missing file error, got %v", err)
+	}
+
+	// TLS certificates are checked up front
+	tls := writeConfig(t, dir, "tls.conf", "[app]\nFactomdTlsEnabled = true\nFactomdTlsPublicCert = \""+
+		filepath.Join(dir, "missing.cert")+"\"\n")
+	_, err = LoadConfig(tls)
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "factomd TLS certificate") {
+		t.Errorf("expected a TLS certificate error, got %v", err)
+	}
+
+	cert := writeConfig(t, dir, "factomdAPIpub.cert", "cert")
+	tls = writeConfig(t, dir, "tls.conf", "[app]\nFactomdTlsEnabled = true\nFactomdTlsPublicCert = "+cert+"\n")
+	if _, err := LoadConfig(tls); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	// FactomdLocation overrides PortNumber, whichever section comes first
+	for _, conf := range []string{
+		"[app]\nPortNumber = 8188\n[Walletd]\nFactomdLocation = factomd.test:8088\n",
+		"[Walletd]\nFactomdLocation = factomd.test:8088\n[app]\nPortNumber = 8188\n",
+	} {
+		cfg := NewRPCConfig()
+		if err := cfg.Load(strings.NewReader(conf)); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.FactomdServer != "factomd.test:8088" {
+			t.Errorf("expected FactomdLocation to take precedence, got %s for\n%s", cfg.FactomdServer, conf)
+		}
+	}
+
+	cfg := NewRPCConfig()
+	if err := cfg.Load(strings.NewReader("[app]\nPortNumber = 8188\n")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FactomdServer != "localhost:8188" {
+		t.Errorf("expected the PortNumber alone to set the server, got %s", cfg.FactomdServer)
+	}
+}
+
+func TestLoadConfigTLSPathsExpanded(t *testing.T) {
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	os.Setenv("HOME", "/home/factom")
+
+	env := map[string]string{
+		"FACTOMD_TLS_CA":          "~/ca.pem, /etc/ca.pem",
+		"FACTOMD_TLS_CLIENT_CERT": "~/client.cert",
+		"FACTOMD_TLS_CLIENT_KEY":  "~/client.key",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	cfg := NewRPCConfig()
+	if err := cfg.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	tls := cfg.FactomdTLS
+	if tls == nil {
+		t.Fatal("expected a factomd TLSConfig")
+	}
+	if strings.Join(tls.CAFiles, ",") != "/home/factom/ca.pem,/etc/ca.pem" {
+		t.Errorf("unexpected CA files %v", tls.CAFiles)
+	}
+	if tls.ClientCertFile != "/home/factom/client.cert" || tls.ClientKeyFile != "/home/factom/client.key" {
+		t.Errorf("unexpected client certificate %s %s", tls.ClientCertFile, tls.ClientKeyFile)
+	}
+}
@@ -5,7 +5,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -42,20 +42,12 @@ func (c *CurrentMinuteInfo) String() string {
 }
 
 // GetCurrentMinute gets the current network information from the factom daemon.
-func GetCurrentMinute() (*CurrentMinuteInfo, error) {
+func (c *Client) GetCurrentMinute(ctx context.Context) (*CurrentMinuteInfo, error) {
 	req := NewJSON2Request("current-minute", APICounter(), nil)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
-	c := new(CurrentMinuteInfo)
-	if err := json.Unmarshal(resp.JSONResult(), c); err != nil {
+	m := new(CurrentMinuteInfo)
+	if err := c.factomdCall(ctx, req, m); err != nil {
 		return nil, err
 	}
 
-	return c, nil
+	return m, nil
 }
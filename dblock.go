@@ -5,7 +5,10 @@
 package factom
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 )
 
@@ -60,23 +63,181 @@ func (db *DBlock) String() string {
 	return s
 }
 
+// dblockHeaderSize is the size of the binary Directory Block header.
+const dblockHeaderSize = 113
+
+// UnmarshalBinary decodes a binary Directory Block, such as the raw data
+// returned by GetRaw. The KeyMR, HeaderHash and DBHash are computed from the
+// decoded block.
+func (db *DBlock) UnmarshalBinary(data []byte) error {
+	_, err := db.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData decodes a binary Directory Block and returns the
+// remainder of the byte stream. An error is returned if the BodyMR in the
+// header does not match the entries in the body.
+func (db *DBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	r := newBlockReader(data)
+
+	n := new(DBlock)
+	n.Header.Version = int(r.uint8("Version"))
+	n.Header.NetworkID = int(r.uint32("NetworkID"))
+	n.Header.BodyMR = r.hash("BodyMR")
+	n.Header.PrevKeyMR = r.hash("PrevKeyMR")
+	n.Header.PrevFullHash = r.hash("PrevFullHash")
+	n.Header.Timestamp = int(r.uint32("Timestamp"))
+	n.Header.DBHeight = int(r.uint32("DBHeight"))
+	n.Header.BlockCount = int(r.uint32("BlockCount"))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// each entry is a 32 byte ChainID and a 32 byte KeyMR
+	if l := len(r.remaining()) / 64; n.Header.BlockCount > l {
+		return nil, fmt.Errorf("%w: BlockCount is %d but there is only room for %d entries",
+			ErrBlockTruncated, n.Header.BlockCount, l)
+	}
+	for i := 0; i < n.Header.BlockCount; i++ {
+		chainID := r.hash(fmt.Sprintf("DBEntries[%d].ChainID", i))
+		keyMR := r.hash(fmt.Sprintf("DBEntries[%d].KeyMR", i))
+		n.DBEntries = append(n.DBEntries, struct {
+			ChainID string `json:"chainid"`
+			KeyMR   string `json:"keymr"`
+		}{chainID, keyMR})
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	bodyMR, err := n.ComputeBodyMR()
+	if err != nil {
+		return nil, err
+	}
+	if hex.EncodeToString(bodyMR) != n.Header.BodyMR {
+		return nil, fmt.Errorf("%w: header has %s, body has %x",
+			ErrBodyMRMismatch, n.Header.BodyMR, bodyMR)
+	}
+
+	headerHash := sha(data[:dblockHeaderSize])
+	n.HeaderHash = hex.EncodeToString(headerHash)
+	n.KeyMR = hex.EncodeToString(sha(append(headerHash, bodyMR...)))
+	n.DBHash = hex.EncodeToString(sha(data[:r.off]))
+	n.SequenceNumber = int64(n.Header.DBHeight)
+
+	*db = *n
+	return r.remaining(), nil
+}
+
+// MarshalBinary encodes the Directory Block in the binary form used by
+// factomd.
+func (db *DBlock) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	h, err := db.marshalHeader()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(h)
+
+	for i, v := range db.DBEntries {
+		if err := writeHash(buf, v.ChainID, fmt.Sprintf("DBEntries[%d].ChainID", i)); err != nil {
+			return nil, err
+		}
+		if err := writeHash(buf, v.KeyMR, fmt.Sprintf("DBEntries[%d].KeyMR", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (db *DBlock) marshalHeader() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// 1 byte Version
+	buf.WriteByte(byte(db.Header.Version))
+
+	// 4 byte NetworkID
+	binary.Write(buf, binary.BigEndian, uint32(db.Header.NetworkID))
+
+	// 32 byte BodyMR, PrevKeyMR and PrevFullHash
+	if err := writeHash(buf, db.Header.BodyMR, "BodyMR"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, db.Header.PrevKeyMR, "PrevKeyMR"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, db.Header.PrevFullHash, "PrevFullHash"); err != nil {
+		return nil, err
+	}
+
+	// 4 byte Timestamp in minutes, DBHeight and BlockCount
+	binary.Write(buf, binary.BigEndian, uint32(db.Header.Timestamp))
+	binary.Write(buf, binary.BigEndian, uint32(db.Header.DBHeight))
+	binary.Write(buf, binary.BigEndian, uint32(len(db.DBEntries)))
+
+	return buf.Bytes(), nil
+}
+
+// ComputeBodyMR computes the Merkle root of the entries of the Directory
+// Block.
+func (db *DBlock) ComputeBodyMR() ([]byte, error) {
+	hashes := make([][]byte, 0, len(db.DBEntries))
+	for i, v := range db.DBEntries {
+		buf := new(bytes.Buffer)
+		if err := writeHash(buf, v.ChainID, fmt.Sprintf("DBEntries[%d].ChainID", i)); err != nil {
+			return nil, err
+		}
+		if err := writeHash(buf, v.KeyMR, fmt.Sprintf("DBEntries[%d].KeyMR", i)); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, sha(buf.Bytes()))
+	}
+	return merkleRoot(hashes), nil
+}
+
+// ComputeHeaderHash computes the hash of the Directory Block header.
+func (db *DBlock) ComputeHeaderHash() ([]byte, error) {
+	h, err := db.marshalHeader()
+	if err != nil {
+		return nil, err
+	}
+	return sha(h), nil
+}
+
+// ComputeKeyMR computes the Key Merkle Root of the Directory Block from its
+// header and the BodyMR of its entries.
+func (db *DBlock) ComputeKeyMR() ([]byte, error) {
+	h, err := db.ComputeHeaderHash()
+	if err != nil {
+		return nil, err
+	}
+	bodyMR, err := db.ComputeBodyMR()
+	if err != nil {
+		return nil, err
+	}
+	return sha(append(h, bodyMR...)), nil
+}
+
+// ComputeFullHash computes the hash of the whole binary Directory Block.
+func (db *DBlock) ComputeFullHash() ([]byte, error) {
+	p, err := db.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sha(p), nil
+}
+
 // TODO: GetDBlock should use the dblock api call directy instead of
 // re-directing to dblock-by-height.
 // we either need to change the "directoy-block" API call or add a new call to
 // return the propper information (it should match the dblock-by-height call)
 
 // GetDBlock requests a Directory Block by its Key Merkle Root from the factomd
-func GetDBlock(keymr string) (dblock *DBlock, err error) {
+func (c *Client) GetDBlock(ctx context.Context, keymr string) (dblock *DBlock, err error) {
 	params := keyMRRequest{KeyMR: keymr}
 	req := NewJSON2Request("directory-block", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	db := new(struct {
 		DBHash string `json:"dbhash"`
 		Header struct {
@@ -89,36 +250,24 @@ func GetDBlock(keymr string) (dblock *DBlock, err error) {
 			KeyMR   string `json:"keymr"`
 		} `json:"entryblocklist"`
 	})
-
-	err = json.Unmarshal(resp.JSONResult(), db)
-	if err != nil {
+	if err = c.factomdCall(ctx, req, db); err != nil {
 		return
 	}
 
 	// TODO: we need a better api call for dblock by keymr so that API will
 	// retrun the same as dblock-byheight
-	return GetDBlockByHeight(db.Header.SequenceNumber)
+	return c.GetDBlockByHeight(ctx, db.Header.SequenceNumber)
 }
 
 // GetDBlockByHeight requests a Directory Block by its block height from the factomd
 // API.
-func GetDBlockByHeight(height int64) (dblock *DBlock, err error) {
+func (c *Client) GetDBlockByHeight(ctx context.Context, height int64) (dblock *DBlock, err error) {
 	params := heightRequest{Height: height, NoRaw: true}
 	req := NewJSON2Request("dblock-by-height", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	wrap := new(struct {
 		DBlock *DBlock `json:"dblock"`
 	})
-
-	err = json.Unmarshal(resp.JSONResult(), wrap)
-	if err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
@@ -128,20 +277,12 @@ func GetDBlockByHeight(height int64) (dblock *DBlock, err error) {
 
 // GetDBlockHead requests the most recent Directory Block Key Merkel Root
 // created by the Factom Network.
-func GetDBlockHead() (string, error) {
+func (c *Client) GetDBlockHead(ctx context.Context) (string, error) {
 	req := NewJSON2Request("directory-block-head", APICounter(), nil)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != nil {
-		return "", resp.Error
-	}
-
 	head := new(struct {
 		KeyMR string `json:"keymr"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), head); err != nil {
+	if err := c.factomdCall(ctx, req, head); err != nil {
 		return "", err
 	}
 
@@ -149,21 +290,11 @@ func GetDBlockHead() (string, error) {
 }
 
 // ReplayDBlockFromHeight requests DBlock states to be emitted over the LiveFeed API
-func ReplayDBlockFromHeight(startheight int64, endheight int64) (*replayResponse, error) {
+func (c *Client) ReplayDBlockFromHeight(ctx context.Context, startheight int64, endheight int64) (*replayResponse, error) {
 	params := replayRequest{StartHeight: startheight, EndHeight: endheight}
 	req := NewJSON2Request("replay-from-height", APICounter(), params)
-	resp, err := factomdRequest(req)
-
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	finalResp := new(replayResponse)
-	err = json.Unmarshal(resp.Result, finalResp)
-	if err != nil {
+	if err := c.factomdCall(ctx, req, finalResp); err != nil {
 		return nil, err
 	}
 
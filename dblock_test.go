@@ -5,6 +5,9 @@
 package factom_test
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -96,3 +99,55 @@ func TestGetDBlockHead(t *testing.T) {
 	}
 	t.Log(response)
 }
+
+func TestDBlockUnmarshalBinary(t *testing.T) {
+	raw, _ := hex.DecodeString("00fa92e5a2d0d3ce18a3522d925d6445fc70a3e050d7586106200100c805e3c434c5f9ea35e0e26f41120e2dcb65f9bb6fb61fdfa1beee29e33d0d2110b0ebdb9d9cc05f9b4e60ea451c7f7230e0a7606872b4dadb57859b573e3a201db434504c24ad6089016e83ee0000006400000004000000000000000000000000000000000000000000000000000000000000000acc03cb3558b6b1acd24c5439fadee6523dd2811af82affb60f056df3374b39ae000000000000000000000000000000000000000000000000000000000000000ced01afb79fafba436984a48876082f58e52fec1ccc2920d708ef64ad3beccbbd000000000000000000000000000000000000000000000000000000000000000fd9a1de8b02f686a9d4232fa7c8420aa0d9538969923c8eee812352c402c4db0ddf3ade9eec4b08d5379cc64270c30ea7315d8a8a1a69efe2b98a60ecdd69e604acf8ceaaf70311a6e84d8d7f8d349e5c7958c896afa1c3a4edee09c1f5a80752")
+
+	db := new(DBlock)
+	if err := db.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if db.Header.NetworkID != 4203931042 || db.Header.Timestamp != 24019950 ||
+		db.Header.DBHeight != 100 || db.Header.BlockCount != 4 || db.SequenceNumber != 100 {
+		t.Errorf("unexpected header\n%s", db)
+	}
+	if len(db.DBEntries) != 4 || db.DBEntries[3].KeyMR != "acf8ceaaf70311a6e84d8d7f8d349e5c7958c896afa1c3a4edee09c1f5a80752" {
+		t.Errorf("unexpected entries\n%s", db)
+	}
+	if db.KeyMR != "cde346e7ed87957edfd68c432c984f35596f29c7d23de6f279351cddecd5dc66" {
+		t.Errorf("unexpected KeyMR %s", db.KeyMR)
+	}
+	if db.DBHash != "ba79704908f6e96a0aeeceeedd8591cf0949bc538cd5df69b1be7ea8095ed778" {
+		t.Errorf("unexpected DBHash %s", db.DBHash)
+	}
+
+	// the hashes computed from the fields match the decoded ones
+	if b, err := db.ComputeBodyMR(); err != nil || hex.EncodeToString(b) != db.Header.BodyMR {
+		t.Errorf("unexpected BodyMR %x %v", b, err)
+	}
+	if h, err := db.ComputeHeaderHash(); err != nil || hex.EncodeToString(h) != db.HeaderHash {
+		t.Errorf("unexpected HeaderHash %x %v", h, err)
+	}
+	if k, err := db.ComputeKeyMR(); err != nil || hex.EncodeToString(k) != db.KeyMR {
+		t.Errorf("unexpected KeyMR %x %v", k, err)
+	}
+	if f, err := db.ComputeFullHash(); err != nil || hex.EncodeToString(f) != db.DBHash {
+		t.Errorf("unexpected full hash %x %v", f, err)
+	}
+	if p, err := db.MarshalBinary(); err != nil || !bytes.Equal(p, raw) {
+		t.Errorf("expected:%x\nrecieved:%x %v", raw, p, err)
+	}
+
+	// a changed entry no longer matches the BodyMR
+	bad := append([]byte(nil), raw...)
+	bad[len(bad)-1] ^= 1
+	if err := new(DBlock).UnmarshalBinary(bad); !errors.Is(err, ErrBodyMRMismatch) {
+		t.Errorf("expected %v, got %v", ErrBodyMRMismatch, err)
+	}
+
+	for _, l := range []int{0, 50, 113, len(raw) - 1} {
+		if err := new(DBlock).UnmarshalBinary(raw[:l]); !errors.Is(err, ErrBlockTruncated) {
+			t.Errorf("%d bytes: expected %v, got %v", l, ErrBlockTruncated, err)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// The factomd debug API is served at /debug instead of /v2. It is meant for
+// the operators of a factomd node and may be disabled on public nodes.
+
+// DebugMessages is a list of the factomd messages returned by the debug API,
+// such as the holding queue. The messages are kept in their JSON form as their
+// fields depend on the message type.
+type DebugMessages struct {
+	Messages []json.RawMessage `json:"Messages"`
+}
+
+func (m *DebugMessages) String() string {
+	var s string
+
+	s += fmt.Sprintln("Messages {")
+	for _, msg := range m.Messages {
+		b := new(bytes.Buffer)
+		if err := json.Compact(b, msg); err != nil {
+			s += fmt.Sprintln("", string(msg))
+			continue
+		}
+		s += fmt.Sprintln("", b.String())
+	}
+	s += fmt.Sprintln("}") // Messages
+
+	return s
+}
+
+// NetworkInfo identifies the Factom Network a factomd node is running on.
+type NetworkInfo struct {
+	NetworkNumber int    `json:"NetworkNumber"`
+	NetworkName   string `json:"NetworkName"`
+	NetworkID     uint32 `json:"NetworkID"`
+}
+
+func (n *NetworkInfo) String() string {
+	var s string
+
+	s += fmt.Sprintln("NetworkNumber:", n.NetworkNumber)
+	s += fmt.Sprintln("NetworkName:", n.NetworkName)
+	s += fmt.Sprintln("NetworkID:", n.NetworkID)
+
+	return s
+}
+
+// AuthorityServer is a federated or audit server of the Factom Network.
+type AuthorityServer struct {
+	ChainID string `json:"ChainID"`
+	Name    string `json:"Name"`
+	Online  bool   `json:"Online"`
+	Replace string `json:"Replace"`
+}
+
+func (a *AuthorityServer) String() string {
+	var s string
+
+	s += fmt.Sprintln("ChainID:", a.ChainID)
+	s += fmt.Sprintln("Name:", a.Name)
+	s += fmt.Sprintln("Online:", a.Online)
+	s += fmt.Sprintln("Replace:", a.Replace)
+
+	return s
+}
+
+// FactomdConfiguration is the configuration factomd is running with, by
+// section of the factomd.conf file and setting name.
+type FactomdConfiguration map[string]map[string]interface{}
+
+func (f FactomdConfiguration) String() string {
+	var s string
+
+	sections := make([]string, 0, len(f))
+	for section := range f {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	for _, section := range sections {
+		s += fmt.Sprintln(section, "{")
+		keys := make([]string, 0, len(f[section]))
+		for k := range f[section] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s += fmt.Sprintln(" "+k+":", f[section][k])
+		}
+		s += fmt.Sprintln("}")
+	}
+
+	return s
+}
+
+// GetHoldingQueue requests the messages factomd holds until they can be
+// processed.
+func (c *Client) GetHoldingQueue(ctx context.Context) (*DebugMessages, error) {
+	req := NewJSON2Request("holding-queue", APICounter(), nil)
+
+	m := new(DebugMessages)
+	if err := c.debugCall(ctx, req, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetNetworkInfo requests the Factom Network factomd is running on.
+func (c *Client) GetNetworkInfo(ctx context.Context) (*NetworkInfo, error) {
+	req := NewJSON2Request("network-info", APICounter(), nil)
+
+	n := new(NetworkInfo)
+	if err := c.debugCall(ctx, req, n); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// GetPredictiveFER requests the Factoid Exchange Rate factomd predicts for
+// the next block.
+func (c *Client) GetPredictiveFER(ctx context.Context) (uint64, error) {
+	req := NewJSON2Request("predictive-fer", APICounter(), nil)
+
+	r := new(struct {
+		PredictiveFER uint64 `json:"PredictiveFER"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return 0, err
+	}
+
+	return r.PredictiveFER, nil
+}
+
+// GetAuditServers requests the audit servers of the Factom Network.
+func (c *Client) GetAuditServers(ctx context.Context) ([]*AuthorityServer, error) {
+	req := NewJSON2Request("audit-servers", APICounter(), nil)
+
+	r := new(struct {
+		AuditServers []*AuthorityServer `json:"AuditServers"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return nil, err
+	}
+
+	return r.AuditServers, nil
+}
+
+// GetFederatedServers requests the federated servers of the Factom Network.
+func (c *Client) GetFederatedServers(ctx context.Context) ([]*AuthorityServer, error) {
+	req := NewJSON2Request("federated-servers", APICounter(), nil)
+
+	r := new(struct {
+		FederatedServers []*AuthorityServer `json:"FederatedServers"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return nil, err
+	}
+
+	return r.FederatedServers, nil
+}
+
+// GetConfiguration requests the configuration factomd is running with.
+func (c *Client) GetConfiguration(ctx context.Context) (FactomdConfiguration, error) {
+	req := NewJSON2Request("configuration", APICounter(), nil)
+
+	f := make(FactomdConfiguration)
+	if err := c.debugCall(ctx, req, &f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ReloadConfiguration has factomd read its configuration file again and
+// returns the resulting configuration.
+func (c *Client) ReloadConfiguration(ctx context.Context) (FactomdConfiguration, error) {
+	req := NewJSON2Request("reload-configuration", APICounter(), nil)
+
+	f := make(FactomdConfiguration)
+	if err := c.debugCall(ctx, req, &f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// GetProcessList requests a text dump of the current process lists of
+// factomd.
+func (c *Client) GetProcessList(ctx context.Context) (string, error) {
+	req := NewJSON2Request("process-list", APICounter(), nil)
+
+	r := new(struct {
+		ProcessList string `json:"ProcessList"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return "", err
+	}
+
+	return r.ProcessList, nil
+}
+
+// GetDebugAuthorities requests the authorities known to factomd through the
+// debug API.
+func (c *Client) GetDebugAuthorities(ctx context.Context) ([]*Authority, error) {
+	req := NewJSON2Request("authorities", APICounter(), nil)
+
+	r := new(struct {
+		Authorities []*Authority `json:"Authorities"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return nil, err
+	}
+
+	return r.Authorities, nil
+}
+
+// GetSummary requests a text summary of the state of factomd.
+func (c *Client) GetSummary(ctx context.Context) (string, error) {
+	req := NewJSON2Request("summary", APICounter(), nil)
+
+	r := new(struct {
+		Summary string `json:"Summary"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return "", err
+	}
+
+	return r.Summary, nil
+}
+
+// GetMessages requests the recent messages of factomd.
+func (c *Client) GetMessages(ctx context.Context) (*DebugMessages, error) {
+	req := NewJSON2Request("messages", APICounter(), nil)
+
+	m := new(DebugMessages)
+	if err := c.debugCall(ctx, req, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetDropRate requests the rate at which factomd drops network messages, for
+// testing.
+func (c *Client) GetDropRate(ctx context.Context) (int, error) {
+	req := NewJSON2Request("drop-rate", APICounter(), nil)
+
+	r := new(struct {
+		DropRate int `json:"DropRate"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return 0, err
+	}
+
+	return r.DropRate, nil
+}
+
+// SetDropRate sets the rate at which factomd drops network messages, for
+// testing, and returns the new rate.
+func (c *Client) SetDropRate(ctx context.Context, rate int) (int, error) {
+	params := struct {
+		DropRate int `json:"DropRate"`
+	}{DropRate: rate}
+	req := NewJSON2Request("set-drop-rate", APICounter(), params)
+
+	r := new(struct {
+		DropRate int `json:"DropRate"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return 0, err
+	}
+
+	return r.DropRate, nil
+}
+
+// GetDelay requests the delay factomd adds to network messages, for testing.
+func (c *Client) GetDelay(ctx context.Context) (int64, error) {
+	req := NewJSON2Request("delay", APICounter(), nil)
+
+	r := new(struct {
+		Delay int64 `json:"Delay"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return 0, err
+	}
+
+	return r.Delay, nil
+}
+
+// SetDelay sets the delay factomd adds to network messages, for testing, and
+// returns the new delay.
+func (c *Client) SetDelay(ctx context.Context, delay int64) (int64, error) {
+	params := struct {
+		Delay int64 `json:"Delay"`
+	}{Delay: delay}
+	req := NewJSON2Request("set-delay", APICounter(), params)
+
+	r := new(struct {
+		Delay int64 `json:"Delay"`
+	})
+	if err := c.debugCall(ctx, req, r); err != nil {
+		return 0, err
+	}
+
+	return r.Delay, nil
+}
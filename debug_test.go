@@ -0,0 +1,149 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+var debugResults = map[string]string{
+	"holding-queue":        `{"Messages":[{"messagetype":17,"hash": "01"},{"messagetype":3}]}`,
+	"network-info":         `{"NetworkNumber":0,"NetworkName":"MAIN","NetworkID":4203931042}`,
+	"predictive-fer":       `{"PredictiveFER":2000}`,
+	"audit-servers":        `{"AuditServers":[{"ChainID":"8888880834dfe56c8c5827026eec58a8a71e3496fb76035c8710f7b708a3daf6","Name":"","Online":true,"Replace":null}]}`,
+	"federated-servers":    `{"FederatedServers":[{"ChainID":"8888880180b0290bbb670e399af48e57a227c939a2d20f6b0e147d24f995a6ef","Name":"","Online":false,"Replace":null}]}`,
+	"configuration":        `{"App":{"PortNumber":8088,"Network":"MAIN"},"Log":{"LogLevel":"error"}}`,
+	"reload-configuration": `{"App":{"PortNumber":8088,"Network":"LOCAL"}}`,
+	"process-list":         `{"ProcessList":"===ProcessListStart===\n"}`,
+	"authorities":          `{"Authorities":[{"chainid":"8888880180b0290bbb670e399af48e57a227c939a2d20f6b0e147d24f995a6ef","manageid":"","matroyshka":"","signingkey":"","status":"federated","anchorkeys":[]}]}`,
+	"summary":              `{"Summary":"FNode0 summary"}`,
+	"messages":             `{"Messages":[{"messagetype":0}]}`,
+	"drop-rate":            `{"DropRate":0}`,
+	"delay":                `{"Delay":0}`,
+}
+
+func debugHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug" {
+			t.Errorf("expected a request to /debug, got %s", r.URL.Path)
+		}
+		req := new(struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		})
+		json.NewDecoder(r.Body).Decode(req)
+
+		result, ok := debugResults[req.Method]
+		switch req.Method {
+		case "set-drop-rate", "set-delay":
+			// answer with the value that was set
+			result, ok = string(req.Params), true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"error":{"code":-32601,"message":"Method not found"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":0,"result":%s}`, result)
+	}
+}
+
+func TestDebugAPI(t *testing.T) {
+	ts := httptest.NewServer(debugHandler(t))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	ctx := context.Background()
+
+	q, err := c.GetHoldingQueue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Messages) != 2 || !strings.Contains(q.String(), ` {"messagetype":17,"hash":"01"}`) {
+		t.Errorf("unexpected holding queue\n%s", q)
+	}
+
+	n, err := c.GetNetworkInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.NetworkName != "MAIN" || n.NetworkID != 4203931042 {
+		t.Errorf("unexpected network info\n%s", n)
+	}
+
+	if fer, err := c.GetPredictiveFER(ctx); err != nil || fer != 2000 {
+		t.Errorf("expected a predictive FER of 2000, got %d %v", fer, err)
+	}
+
+	audits, err := c.GetAuditServers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audits) != 1 || !audits[0].Online {
+		t.Errorf("unexpected audit servers %v", audits)
+	}
+	feds, err := c.GetFederatedServers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feds) != 1 || feds[0].ChainID[:6] != "888888" {
+		t.Errorf("unexpected federated servers %v", feds)
+	}
+
+	cfg, err := c.GetConfiguration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["App"]["Network"] != "MAIN" {
+		t.Errorf("unexpected configuration\n%s", cfg)
+	}
+	if s := cfg.String(); !strings.HasPrefix(s, "App {\n Network: MAIN\n PortNumber: 8088\n}\nLog {\n") {
+		t.Errorf("unexpected configuration output\n%s", s)
+	}
+	if cfg, err := c.ReloadConfiguration(ctx); err != nil || cfg["App"]["Network"] != "LOCAL" {
+		t.Errorf("unexpected reloaded configuration %v %v", cfg, err)
+	}
+
+	if pl, err := c.GetProcessList(ctx); err != nil || !strings.HasPrefix(pl, "===ProcessListStart===") {
+		t.Errorf("unexpected process list %q %v", pl, err)
+	}
+	if s, err := c.GetSummary(ctx); err != nil || s != "FNode0 summary" {
+		t.Errorf("unexpected summary %q %v", s, err)
+	}
+
+	as, err := c.GetDebugAuthorities(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 1 || as[0].Status != "federated" {
+		t.Errorf("unexpected authorities %v", as)
+	}
+
+	if m, err := c.GetMessages(ctx); err != nil || len(m.Messages) != 1 {
+		t.Errorf("unexpected messages %v %v", m, err)
+	}
+
+	if r, err := c.GetDropRate(ctx); err != nil || r != 0 {
+		t.Errorf("unexpected drop rate %d %v", r, err)
+	}
+	if r, err := c.SetDropRate(ctx, 500); err != nil || r != 500 {
+		t.Errorf("expected a drop rate of 500, got %d %v", r, err)
+	}
+	if d, err := c.GetDelay(ctx); err != nil || d != 0 {
+		t.Errorf("unexpected delay %d %v", d, err)
+	}
+	if d, err := c.SetDelay(ctx, 100); err != nil || d != 100 {
+		t.Errorf("expected a delay of 100, got %d %v", d, err)
+	}
+}
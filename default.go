@@ -0,0 +1,584 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"context"
+)
+
+// The package level API functions are wrappers around a Client or WalletClient
+// created from the package level RpcConfig. They use context.Background(); use
+// the Client and WalletClient methods directly to cancel a call or to give it
+// a deadline.
+
+// SendFactomdRequest sends a json object to factomd
+func SendFactomdRequest(req *JSON2Request) (*JSON2Response, error) {
+	return factomdClient().SendRequest(context.Background(), req)
+}
+
+// SendFactomdBatch sends a batch of json objects to factomd
+func SendFactomdBatch(reqs []*JSON2Request) ([]*JSON2Response, error) {
+	return factomdClient().SendBatch(context.Background(), reqs)
+}
+
+// SendWalletBatch sends a batch of json objects to factom-walletd
+func SendWalletBatch(reqs []*JSON2Request) ([]*JSON2Response, error) {
+	return walletClient().SendBatch(context.Background(), reqs)
+}
+
+// GetABlock is a wrapper around Client.GetABlock using the package level RpcConfig.
+func GetABlock(keymr string) (ablock *ABlock, err error) {
+	return factomdClient().GetABlock(context.Background(), keymr)
+}
+
+// GetABlockByHeight is a wrapper around Client.GetABlockByHeight using the package level RpcConfig.
+func GetABlockByHeight(height int64) (ablock *ABlock, err error) {
+	return factomdClient().GetABlockByHeight(context.Background(), height)
+}
+
+// FactoidACK is a wrapper around Client.FactoidACK using the package level RpcConfig.
+func FactoidACK(txID, fullTransaction string) (*FactoidTxStatus, error) {
+	return factomdClient().FactoidACK(context.Background(), txID, fullTransaction)
+}
+
+// EntryCommitACK is a wrapper around Client.EntryCommitACK using the package level RpcConfig.
+func EntryCommitACK(txID, fullTransaction string) (*EntryStatus, error) {
+	return factomdClient().EntryCommitACK(context.Background(), txID, fullTransaction)
+}
+
+// EntryRevealACK is a wrapper around Client.EntryRevealACK using the package level RpcConfig.
+func EntryRevealACK(entryhash, fullTransaction, chainiID string) (*EntryStatus, error) {
+	return factomdClient().EntryRevealACK(context.Background(), entryhash, fullTransaction, chainiID)
+}
+
+// GetAnchors is a wrapper around Client.GetAnchors using the package level RpcConfig.
+func GetAnchors(hash string) (*Anchors, error) {
+	return factomdClient().GetAnchors(context.Background(), hash)
+}
+
+// GetAnchorsByHeight is a wrapper around Client.GetAnchorsByHeight using the package level RpcConfig.
+func GetAnchorsByHeight(height int64) (*Anchors, error) {
+	return factomdClient().GetAnchorsByHeight(context.Background(), height)
+}
+
+// GetAuthorities is a wrapper around Client.GetAuthorities using the package level RpcConfig.
+func GetAuthorities() ([]*Authority, error) {
+	return factomdClient().GetAuthorities(context.Background())
+}
+
+// GetECBalance is a wrapper around Client.GetECBalance using the package level RpcConfig.
+func GetECBalance(addr string) (int64, error) {
+	return factomdClient().GetECBalance(context.Background(), addr)
+}
+
+// GetFactoidBalance is a wrapper around Client.GetFactoidBalance using the package level RpcConfig.
+func GetFactoidBalance(addr string) (int64, error) {
+	return factomdClient().GetFactoidBalance(context.Background(), addr)
+}
+
+// GetBalanceTotals is a wrapper around WalletClient.GetBalanceTotals using the package level RpcConfig.
+func GetBalanceTotals() (fs, fa, es, ea int64, err error) {
+	return walletClient().GetBalanceTotals(context.Background())
+}
+
+// GetWalletBalances is a wrapper around WalletClient.GetWalletBalances using the package level RpcConfig.
+func GetWalletBalances() (*WalletBalances, error) {
+	return walletClient().GetWalletBalances(context.Background())
+}
+
+// GetMultipleFCTBalances is a wrapper around Client.GetMultipleFCTBalances using the package level RpcConfig.
+func GetMultipleFCTBalances(fas ...string) (*MultiBalanceResponse, error) {
+	return factomdClient().GetMultipleFCTBalances(context.Background(), fas...)
+}
+
+// GetMultipleECBalances is a wrapper around Client.GetMultipleECBalances using the package level RpcConfig.
+func GetMultipleECBalances(ecs ...string) (*MultiBalanceResponse, error) {
+	return factomdClient().GetMultipleECBalances(context.Background(), ecs...)
+}
+
+// GetBlockByHeightRaw is a wrapper around Client.GetBlockByHeightRaw using the package level RpcConfig.
+func GetBlockByHeightRaw(blockType string, height int64) (*BlockByHeightRawResponse, error) {
+	return factomdClient().GetBlockByHeightRaw(context.Background(), blockType, height)
+}
+
+// ChainExists is a wrapper around Client.ChainExists using the package level RpcConfig.
+func ChainExists(chainid string) bool {
+	return factomdClient().ChainExists(context.Background(), chainid)
+}
+
+// CommitChain is a wrapper around Client.CommitChain using the package level RpcConfig.
+func CommitChain(chain *Chain, ec *ECAddress) (string, error) {
+	return factomdClient().CommitChain(context.Background(), chain, ec)
+}
+
+// RevealChain is a wrapper around Client.RevealChain using the package level RpcConfig.
+func RevealChain(chain *Chain) (string, error) {
+	return factomdClient().RevealChain(context.Background(), chain)
+}
+
+// GetChainHead is a wrapper around Client.GetChainHead using the package level RpcConfig.
+func GetChainHead(chainid string) (string, bool, error) {
+	return factomdClient().GetChainHead(context.Background(), chainid)
+}
+
+// GetAllChainEntries is a wrapper around Client.GetAllChainEntries using the package level RpcConfig.
+func GetAllChainEntries(chainid string) ([]*Entry, error) {
+	return factomdClient().GetAllChainEntries(context.Background(), chainid)
+}
+
+// GetAllChainEntriesAtHeight is a wrapper around Client.GetAllChainEntriesAtHeight using the package level RpcConfig.
+func GetAllChainEntriesAtHeight(chainid string, height int64) ([]*Entry, error) {
+	return factomdClient().GetAllChainEntriesAtHeight(context.Background(), chainid, height)
+}
+
+// GetFirstEntry is a wrapper around Client.GetFirstEntry using the package level RpcConfig.
+func GetFirstEntry(chainid string) (*Entry, error) {
+	return factomdClient().GetFirstEntry(context.Background(), chainid)
+}
+
+// GetCurrentMinute is a wrapper around Client.GetCurrentMinute using the package level RpcConfig.
+func GetCurrentMinute() (*CurrentMinuteInfo, error) {
+	return factomdClient().GetCurrentMinute(context.Background())
+}
+
+// GetDBlock is a wrapper around Client.GetDBlock using the package level RpcConfig.
+func GetDBlock(keymr string) (dblock *DBlock, err error) {
+	return factomdClient().GetDBlock(context.Background(), keymr)
+}
+
+// GetDBlockByHeight is a wrapper around Client.GetDBlockByHeight using the package level RpcConfig.
+func GetDBlockByHeight(height int64) (dblock *DBlock, err error) {
+	return factomdClient().GetDBlockByHeight(context.Background(), height)
+}
+
+// GetDBlockHead is a wrapper around Client.GetDBlockHead using the package level RpcConfig.
+func GetDBlockHead() (string, error) {
+	return factomdClient().GetDBlockHead(context.Background())
+}
+
+// ReplayDBlockFromHeight is a wrapper around Client.ReplayDBlockFromHeight using the package level RpcConfig.
+func ReplayDBlockFromHeight(startheight int64, endheight int64) (*replayResponse, error) {
+	return factomdClient().ReplayDBlockFromHeight(context.Background(), startheight, endheight)
+}
+
+// GetDiagnostics is a wrapper around Client.GetDiagnostics using the package level RpcConfig.
+func GetDiagnostics() (*Diagnostics, error) {
+	return factomdClient().GetDiagnostics(context.Background())
+}
+
+// GetHoldingQueue is a wrapper around Client.GetHoldingQueue using the package level RpcConfig.
+func GetHoldingQueue() (*DebugMessages, error) {
+	return factomdClient().GetHoldingQueue(context.Background())
+}
+
+// GetNetworkInfo is a wrapper around Client.GetNetworkInfo using the package level RpcConfig.
+func GetNetworkInfo() (*NetworkInfo, error) {
+	return factomdClient().GetNetworkInfo(context.Background())
+}
+
+// GetPredictiveFER is a wrapper around Client.GetPredictiveFER using the package level RpcConfig.
+func GetPredictiveFER() (uint64, error) {
+	return factomdClient().GetPredictiveFER(context.Background())
+}
+
+// GetAuditServers is a wrapper around Client.GetAuditServers using the package level RpcConfig.
+func GetAuditServers() ([]*AuthorityServer, error) {
+	return factomdClient().GetAuditServers(context.Background())
+}
+
+// GetFederatedServers is a wrapper around Client.GetFederatedServers using the package level RpcConfig.
+func GetFederatedServers() ([]*AuthorityServer, error) {
+	return factomdClient().GetFederatedServers(context.Background())
+}
+
+// GetConfiguration is a wrapper around Client.GetConfiguration using the package level RpcConfig.
+func GetConfiguration() (FactomdConfiguration, error) {
+	return factomdClient().GetConfiguration(context.Background())
+}
+
+// ReloadConfiguration is a wrapper around Client.ReloadConfiguration using the package level RpcConfig.
+func ReloadConfiguration() (FactomdConfiguration, error) {
+	return factomdClient().ReloadConfiguration(context.Background())
+}
+
+// GetProcessList is a wrapper around Client.GetProcessList using the package level RpcConfig.
+func GetProcessList() (string, error) {
+	return factomdClient().GetProcessList(context.Background())
+}
+
+// GetDebugAuthorities is a wrapper around Client.GetDebugAuthorities using the package level RpcConfig.
+func GetDebugAuthorities() ([]*Authority, error) {
+	return factomdClient().GetDebugAuthorities(context.Background())
+}
+
+// GetSummary is a wrapper around Client.GetSummary using the package level RpcConfig.
+func GetSummary() (string, error) {
+	return factomdClient().GetSummary(context.Background())
+}
+
+// GetMessages is a wrapper around Client.GetMessages using the package level RpcConfig.
+func GetMessages() (*DebugMessages, error) {
+	return factomdClient().GetMessages(context.Background())
+}
+
+// GetDropRate is a wrapper around Client.GetDropRate using the package level RpcConfig.
+func GetDropRate() (int, error) {
+	return factomdClient().GetDropRate(context.Background())
+}
+
+// SetDropRate is a wrapper around Client.SetDropRate using the package level RpcConfig.
+func SetDropRate(rate int) (int, error) {
+	return factomdClient().SetDropRate(context.Background(), rate)
+}
+
+// GetDelay is a wrapper around Client.GetDelay using the package level RpcConfig.
+func GetDelay() (int64, error) {
+	return factomdClient().GetDelay(context.Background())
+}
+
+// SetDelay is a wrapper around Client.SetDelay using the package level RpcConfig.
+func SetDelay(delay int64) (int64, error) {
+	return factomdClient().SetDelay(context.Background(), delay)
+}
+
+// GetEBlock is a wrapper around Client.GetEBlock using the package level RpcConfig.
+func GetEBlock(keymr string) (*EBlock, error) {
+	return factomdClient().GetEBlock(context.Background(), keymr)
+}
+
+// GetAllEBlockEntries is a wrapper around Client.GetAllEBlockEntries using the package level RpcConfig.
+func GetAllEBlockEntries(keymr string) ([]*Entry, error) {
+	return factomdClient().GetAllEBlockEntries(context.Background(), keymr)
+}
+
+// GetECBlock is a wrapper around Client.GetECBlock using the package level RpcConfig.
+func GetECBlock(keymr string) (ecblock *ECBlock, err error) {
+	return factomdClient().GetECBlock(context.Background(), keymr)
+}
+
+// GetECBlockByHeight is a wrapper around Client.GetECBlockByHeight using the package level RpcConfig.
+func GetECBlockByHeight(height int64) (ecblock *ECBlock, err error) {
+	return factomdClient().GetECBlockByHeight(context.Background(), height)
+}
+
+// GetECRate is a wrapper around Client.GetECRate using the package level RpcConfig.
+func GetECRate() (uint64, error) {
+	return factomdClient().GetECRate(context.Background())
+}
+
+// CommitEntry is a wrapper around Client.CommitEntry using the package level RpcConfig.
+func CommitEntry(e *Entry, ec *ECAddress) (string, error) {
+	return factomdClient().CommitEntry(context.Background(), e, ec)
+}
+
+// RevealEntry is a wrapper around Client.RevealEntry using the package level RpcConfig.
+func RevealEntry(e *Entry) (string, error) {
+	return factomdClient().RevealEntry(context.Background(), e)
+}
+
+// GetEntry is a wrapper around Client.GetEntry using the package level RpcConfig.
+func GetEntry(hash string) (*Entry, error) {
+	return factomdClient().GetEntry(context.Background(), hash)
+}
+
+// GetPendingEntries is a wrapper around Client.GetPendingEntries using the package level RpcConfig.
+func GetPendingEntries() ([]PendingEntry, error) {
+	return factomdClient().GetPendingEntries(context.Background())
+}
+
+// GetFBlock is a wrapper around Client.GetFBlock using the package level RpcConfig.
+func GetFBlock(keymr string) (fblock *FBlock, err error) {
+	return factomdClient().GetFBlock(context.Background(), keymr)
+}
+
+// GetFBlockByHeight is a wrapper around Client.GetFBlockByHeight using the package level RpcConfig.
+func GetFBlockByHeight(height int64) (fblock *FBlock, err error) {
+	return factomdClient().GetFBlockByHeight(context.Background(), height)
+}
+
+// GetHeights is a wrapper around Client.GetHeights using the package level RpcConfig.
+func GetHeights() (*HeightsResponse, error) {
+	return factomdClient().GetHeights(context.Background())
+}
+
+// GetActiveIdentityKeys is a wrapper around Client.GetActiveIdentityKeys using the package level RpcConfig.
+func GetActiveIdentityKeys(chainID string) ([]string, int64, error) {
+	return factomdClient().GetActiveIdentityKeys(context.Background(), chainID)
+}
+
+// GetActiveIdentityKeysAtHeight is a wrapper around Client.GetActiveIdentityKeysAtHeight using the package level RpcConfig.
+func GetActiveIdentityKeysAtHeight(chainID string, height int64) ([]string, error) {
+	return factomdClient().GetActiveIdentityKeysAtHeight(context.Background(), chainID, height)
+}
+
+// GetProperties is a wrapper around WalletClient.GetProperties using the package level RpcConfig.
+func GetProperties() (*Properties, error) {
+	return walletClient().GetProperties(context.Background())
+}
+
+// GetWalletProperties is a wrapper around WalletClient.GetWalletProperties using the package level RpcConfig.
+func GetWalletProperties() (*Properties, error) {
+	return walletClient().GetWalletProperties(context.Background())
+}
+
+// GetRaw is a wrapper around Client.GetRaw using the package level RpcConfig.
+func GetRaw(keymr string) ([]byte, error) {
+	return factomdClient().GetRaw(context.Background(), keymr)
+}
+
+// SendRawMsg is a wrapper around Client.SendRawMsg using the package level RpcConfig.
+func SendRawMsg(message string) (string, error) {
+	return factomdClient().SendRawMsg(context.Background(), message)
+}
+
+// GetReceipt is a wrapper around Client.GetReceipt using the package level RpcConfig.
+func GetReceipt(hash string) (*Receipt, error) {
+	return factomdClient().GetReceipt(context.Background(), hash)
+}
+
+// GetDnsBalance is a wrapper around Client.GetDnsBalance using the package level RpcConfig.
+func GetDnsBalance(addr string) (int64, int64, error) {
+	return factomdClient().GetDnsBalance(context.Background(), addr)
+}
+
+// SignData is a wrapper around WalletClient.SignData using the package level RpcConfig.
+func SignData(signer string, data []byte) (*Signature, error) {
+	return walletClient().SignData(context.Background(), signer, data)
+}
+
+// GetTPS is a wrapper around Client.GetTPS using the package level RpcConfig.
+func GetTPS() (instant, total float64, err error) {
+	return factomdClient().GetTPS(context.Background())
+}
+
+// GetPendingTransactions is a wrapper around Client.GetPendingTransactions using the package level RpcConfig.
+func GetPendingTransactions() ([]PendingTransaction, error) {
+	return factomdClient().GetPendingTransactions(context.Background())
+}
+
+// NewTransaction is a wrapper around WalletClient.NewTransaction using the package level RpcConfig.
+func NewTransaction(name string) (*Transaction, error) {
+	return walletClient().NewTransaction(context.Background(), name)
+}
+
+// DeleteTransaction is a wrapper around WalletClient.DeleteTransaction using the package level RpcConfig.
+func DeleteTransaction(name string) error {
+	return walletClient().DeleteTransaction(context.Background(), name)
+}
+
+// ListTransactionsAll is a wrapper around WalletClient.ListTransactionsAll using the package level RpcConfig.
+func ListTransactionsAll() ([]*Transaction, error) {
+	return walletClient().ListTransactionsAll(context.Background())
+}
+
+// ListTransactionsAddress is a wrapper around WalletClient.ListTransactionsAddress using the package level RpcConfig.
+func ListTransactionsAddress(addr string) ([]*Transaction, error) {
+	return walletClient().ListTransactionsAddress(context.Background(), addr)
+}
+
+// ListTransactionsID is a wrapper around WalletClient.ListTransactionsID using the package level RpcConfig.
+func ListTransactionsID(id string) ([]*Transaction, error) {
+	return walletClient().ListTransactionsID(context.Background(), id)
+}
+
+// ListTransactionsRange is a wrapper around WalletClient.ListTransactionsRange using the package level RpcConfig.
+func ListTransactionsRange(start, end int) ([]*Transaction, error) {
+	return walletClient().ListTransactionsRange(context.Background(), start, end)
+}
+
+// ListTransactionsTmp is a wrapper around WalletClient.ListTransactionsTmp using the package level RpcConfig.
+func ListTransactionsTmp() ([]*Transaction, error) {
+	return walletClient().ListTransactionsTmp(context.Background())
+}
+
+// AddTransactionInput is a wrapper around WalletClient.AddTransactionInput using the package level RpcConfig.
+func AddTransactionInput(name, address string, amount uint64) (*Transaction, error) {
+	return walletClient().AddTransactionInput(context.Background(), name, address, amount)
+}
+
+// AddTransactionOutput is a wrapper around WalletClient.AddTransactionOutput using the package level RpcConfig.
+func AddTransactionOutput(name, address string, amount uint64) (*Transaction, error) {
+	return walletClient().AddTransactionOutput(context.Background(), name, address, amount)
+}
+
+// AddTransactionECOutput is a wrapper around WalletClient.AddTransactionECOutput using the package level RpcConfig.
+func AddTransactionECOutput(name, address string, amount uint64) (*Transaction, error) {
+	return walletClient().AddTransactionECOutput(context.Background(), name, address, amount)
+}
+
+// AddTransactionFee is a wrapper around WalletClient.AddTransactionFee using the package level RpcConfig.
+func AddTransactionFee(name, address string) (*Transaction, error) {
+	return walletClient().AddTransactionFee(context.Background(), name, address)
+}
+
+// SubTransactionFee is a wrapper around WalletClient.SubTransactionFee using the package level RpcConfig.
+func SubTransactionFee(name, address string) (*Transaction, error) {
+	return walletClient().SubTransactionFee(context.Background(), name, address)
+}
+
+// SignTransaction is a wrapper around WalletClient.SignTransaction using the package level RpcConfig.
+func SignTransaction(name string, force bool) (*Transaction, error) {
+	return walletClient().SignTransaction(context.Background(), name, force)
+}
+
+// ComposeTransaction is a wrapper around WalletClient.ComposeTransaction using the package level RpcConfig.
+func ComposeTransaction(name string) ([]byte, error) {
+	return walletClient().ComposeTransaction(context.Background(), name)
+}
+
+// SendTransaction is a wrapper around WalletClient.SendTransaction using the package level RpcConfig.
+func SendTransaction(name string) (*Transaction, error) {
+	return walletClient().SendTransaction(context.Background(), name)
+}
+
+// SendFactoid is a wrapper around WalletClient.SendFactoid using the package level RpcConfig.
+func SendFactoid(from, to string, amount uint64, force bool) (*Transaction, error) {
+	return walletClient().SendFactoid(context.Background(), from, to, amount, force)
+}
+
+// BuyEC is a wrapper around WalletClient.BuyEC using the package level RpcConfig.
+func BuyEC(from, to string, amount uint64, force bool) (*Transaction, error) {
+	return walletClient().BuyEC(context.Background(), from, to, amount, force)
+}
+
+// BuyExactEC is a wrapper around WalletClient.BuyExactEC using the package level RpcConfig.
+func BuyExactEC(from, to string, amount uint64, force bool) (*Transaction, error) {
+	return walletClient().BuyExactEC(context.Background(), from, to, amount, force)
+}
+
+// FactoidSubmit is a wrapper around Client.FactoidSubmit using the package level RpcConfig.
+func FactoidSubmit(tx string) (message, txid string, err error) {
+	return factomdClient().FactoidSubmit(context.Background(), tx)
+}
+
+// GetTransaction is a wrapper around Client.GetTransaction using the package level RpcConfig.
+func GetTransaction(txID string) (*TransactionResponse, error) {
+	return factomdClient().GetTransaction(context.Background(), txID)
+}
+
+// GetTmpTransaction is a wrapper around WalletClient.GetTmpTransaction using the package level RpcConfig.
+func GetTmpTransaction(name string) (*Transaction, error) {
+	return walletClient().GetTmpTransaction(context.Background(), name)
+}
+
+// BackupWallet is a wrapper around WalletClient.BackupWallet using the package level RpcConfig.
+func BackupWallet() (string, error) {
+	return walletClient().BackupWallet(context.Background())
+}
+
+// GenerateFactoidAddress is a wrapper around WalletClient.GenerateFactoidAddress using the package level RpcConfig.
+func GenerateFactoidAddress() (*FactoidAddress, error) {
+	return walletClient().GenerateFactoidAddress(context.Background())
+}
+
+// GenerateECAddress is a wrapper around WalletClient.GenerateECAddress using the package level RpcConfig.
+func GenerateECAddress() (*ECAddress, error) {
+	return walletClient().GenerateECAddress(context.Background())
+}
+
+// GenerateIdentityKey is a wrapper around WalletClient.GenerateIdentityKey using the package level RpcConfig.
+func GenerateIdentityKey() (*IdentityKey, error) {
+	return walletClient().GenerateIdentityKey(context.Background())
+}
+
+// ImportAddresses is a wrapper around WalletClient.ImportAddresses using the package level RpcConfig.
+func ImportAddresses(addrs ...string) ([]*FactoidAddress, []*ECAddress, error) {
+	return walletClient().ImportAddresses(context.Background(), addrs...)
+}
+
+// ImportKoinify is a wrapper around WalletClient.ImportKoinify using the package level RpcConfig.
+func ImportKoinify(mnemonic string) (*FactoidAddress, error) {
+	return walletClient().ImportKoinify(context.Background(), mnemonic)
+}
+
+// RemoveAddress is a wrapper around WalletClient.RemoveAddress using the package level RpcConfig.
+func RemoveAddress(address string) error {
+	return walletClient().RemoveAddress(context.Background(), address)
+}
+
+// FetchAddresses is a wrapper around WalletClient.FetchAddresses using the package level RpcConfig.
+func FetchAddresses() ([]*FactoidAddress, []*ECAddress, error) {
+	return walletClient().FetchAddresses(context.Background())
+}
+
+// FetchECAddress is a wrapper around WalletClient.FetchECAddress using the package level RpcConfig.
+func FetchECAddress(ecpub string) (*ECAddress, error) {
+	return walletClient().FetchECAddress(context.Background(), ecpub)
+}
+
+// FetchFactoidAddress is a wrapper around WalletClient.FetchFactoidAddress using the package level RpcConfig.
+func FetchFactoidAddress(fctpub string) (*FactoidAddress, error) {
+	return walletClient().FetchFactoidAddress(context.Background(), fctpub)
+}
+
+// ImportIdentityKeys is a wrapper around WalletClient.ImportIdentityKeys using the package level RpcConfig.
+func ImportIdentityKeys(pubs ...string) ([]*IdentityKey, error) {
+	return walletClient().ImportIdentityKeys(context.Background(), pubs...)
+}
+
+// FetchIdentityKey is a wrapper around WalletClient.FetchIdentityKey using the package level RpcConfig.
+func FetchIdentityKey(pub string) (*IdentityKey, error) {
+	return walletClient().FetchIdentityKey(context.Background(), pub)
+}
+
+// FetchIdentityKeys is a wrapper around WalletClient.FetchIdentityKeys using the package level RpcConfig.
+func FetchIdentityKeys() ([]*IdentityKey, error) {
+	return walletClient().FetchIdentityKeys(context.Background())
+}
+
+// RemoveIdentityKey is a wrapper around WalletClient.RemoveIdentityKey using the package level RpcConfig.
+func RemoveIdentityKey(pub string) error {
+	return walletClient().RemoveIdentityKey(context.Background(), pub)
+}
+
+// GetWalletHeight is a wrapper around WalletClient.GetWalletHeight using the package level RpcConfig.
+func GetWalletHeight() (uint32, error) {
+	return walletClient().GetWalletHeight(context.Background())
+}
+
+// UnlockWallet is a wrapper around WalletClient.UnlockWallet using the package level RpcConfig.
+func UnlockWallet(passphrase string, seconds int64) (int64, error) {
+	return walletClient().UnlockWallet(context.Background(), passphrase, seconds)
+}
+
+// WalletComposeChainCommitReveal is a wrapper around WalletClient.WalletComposeChainCommitReveal using the package level RpcConfig.
+func WalletComposeChainCommitReveal(chain *Chain, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+	return walletClient().WalletComposeChainCommitReveal(context.Background(), chain, ecPub, force)
+}
+
+// WalletComposeEntryCommitReveal is a wrapper around WalletClient.WalletComposeEntryCommitReveal using the package level RpcConfig.
+func WalletComposeEntryCommitReveal(entry *Entry, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+	return walletClient().WalletComposeEntryCommitReveal(context.Background(), entry, ecPub, force)
+}
+
+// WalletComposeIdentityChainCommitReveal is a wrapper around WalletClient.WalletComposeIdentityChainCommitReveal using the package level RpcConfig.
+func WalletComposeIdentityChainCommitReveal(name []string, pubKeys []string, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+	return walletClient().WalletComposeIdentityChainCommitReveal(context.Background(), name, pubKeys, ecPub, force)
+}
+
+// WalletComposeIdentityKeyReplacementCommitReveal is a wrapper around WalletClient.WalletComposeIdentityKeyReplacementCommitReveal using the package level RpcConfig.
+func WalletComposeIdentityKeyReplacementCommitReveal(chainID, oldKey, newKey, signerPubKey, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+	return walletClient().WalletComposeIdentityKeyReplacementCommitReveal(context.Background(), chainID, oldKey, newKey, signerPubKey, ecPub, force)
+}
+
+// WalletComposeIdentityAttributeCommitReveal is a wrapper around WalletClient.WalletComposeIdentityAttributeCommitReveal using the package level RpcConfig.
+func WalletComposeIdentityAttributeCommitReveal(receiverChainID, destinationChainID string, attributes []IdentityAttribute, signerPubKey, signerChainID, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+	return walletClient().WalletComposeIdentityAttributeCommitReveal(context.Background(), receiverChainID, destinationChainID, attributes, signerPubKey, signerChainID, ecPub, force)
+}
+
+// WalletComposeIdentityAttributeEndorsementCommitReveal is a wrapper around WalletClient.WalletComposeIdentityAttributeEndorsementCommitReveal using the package level RpcConfig.
+func WalletComposeIdentityAttributeEndorsementCommitReveal(destinationChainID, entryHash, signerPubKey, signerChainID, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+	return walletClient().WalletComposeIdentityAttributeEndorsementCommitReveal(context.Background(), destinationChainID, entryHash, signerPubKey, signerChainID, ecPub, force)
+}
+
+// FetchActiveIdentityKeys is a wrapper around WalletClient.FetchActiveIdentityKeys using the package level RpcConfig.
+func FetchActiveIdentityKeys(chainID string) ([]string, int64, error) {
+	return walletClient().FetchActiveIdentityKeys(context.Background(), chainID)
+}
+
+// FetchActiveIdentityKeysAtHeight is a wrapper around WalletClient.FetchActiveIdentityKeysAtHeight using the package level RpcConfig.
+func FetchActiveIdentityKeysAtHeight(chainID string, height int64) ([]string, error) {
+	return walletClient().FetchActiveIdentityKeysAtHeight(context.Background(), chainID, height)
+}
@@ -5,7 +5,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -105,18 +105,10 @@ func (d *Diagnostics) String() string {
 }
 
 // GetDiagnostics requests diagnostic information from factomd.
-func GetDiagnostics() (*Diagnostics, error) {
+func (c *Client) GetDiagnostics(ctx context.Context) (*Diagnostics, error) {
 	req := NewJSON2Request("diagnostics", APICounter(), nil)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	d := new(Diagnostics)
-	if err := json.Unmarshal(resp.JSONResult(), d); err != nil {
+	if err := c.factomdCall(ctx, req, d); err != nil {
 		return nil, err
 	}
 
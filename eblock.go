@@ -5,19 +5,37 @@
 package factom
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
+// ErrEBlockNotBinary is returned when an Entry Block that was not decoded from
+// binary, such as one from GetEBlock, is encoded or hashed. The entry-block
+// API does not return the BodyMR, PrevFullHash and entry minutes these need;
+// to verify an Entry Block, decode the data from GetRaw with UnmarshalBinary.
+var ErrEBlockNotBinary = errors.New("Entry Block lacks the BodyMR and PrevFullHash of its binary form")
+
 // EBlock is an Entry Block from the Factom Network. An Entry Block contains a
 // series of Entries all belonging to the same Chain on Factom from a given 10
 // minute period. All of the Entry Blocks from a given period are collected into
 // a Merkel Tree the root of which is the Factom Directory Block.
+//
+// The KeyMR, EBHash and the BodyMR and PrevFullHash of the Header are only
+// known for Entry Blocks decoded from binary.
 type EBlock struct {
+	KeyMR  string `json:"keymr,omitempty"`
+	EBHash string `json:"ebhash,omitempty"`
 	Header struct {
 		BlockSequenceNumber int64  `json:"blocksequencenumber"`
 		ChainID             string `json:"chainid"`
+		BodyMR              string `json:"bodymr,omitempty"`
 		PrevKeyMR           string `json:"prevkeymr"`
+		PrevFullHash        string `json:"prevfullhash,omitempty"`
 		Timestamp           int64  `json:"timestamp"`
 		DBHeight            int64  `json:"dbheight"`
 	} `json:"header"`
@@ -31,9 +49,13 @@ type EBlock struct {
 // The consensus algorithm does NOT garuntee that the cannonical order of the
 // EBEntries is the same order that they were recieved for multiple Entries that
 // are made to the network during a single minute.
+//
+// Minute is the minute of the block, 1 to 10, the Entry was added in. It is
+// only known for Entry Blocks decoded from binary.
 type EBEntry struct {
 	EntryHash string `json:"entryhash"`
 	Timestamp int64  `json:"timestamp"`
+	Minute    int    `json:"minute,omitempty"`
 }
 
 func (e *EBlock) String() string {
@@ -47,42 +69,286 @@ func (e *EBlock) String() string {
 		s += fmt.Sprintln("EBEntry {")
 		s += fmt.Sprintln("	Timestamp", v.Timestamp)
 		s += fmt.Sprintln("	EntryHash", v.EntryHash)
+		if v.Minute != 0 {
+			s += fmt.Sprintln("	Minute", v.Minute)
+		}
 		s += fmt.Sprintln("}")
 	}
 	return s
 }
 
-// GetEBlock requests an Entry Block from factomd by its Key Merkle Root
-func GetEBlock(keymr string) (*EBlock, error) {
-	params := keyMRRequest{KeyMR: keymr}
-	req := NewJSON2Request("entry-block", APICounter(), params)
-	resp, err := factomdRequest(req)
+// ebHeaderSize is the size of the binary Entry Block header.
+const ebHeaderSize = 140
+
+// UnmarshalBinary decodes a binary Entry Block, such as the raw data returned
+// by GetRaw. The minute markers of the body set the Minute of each EBEntry and
+// the KeyMR and EBHash are computed from the decoded block.
+func (e *EBlock) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData decodes a binary Entry Block and returns the remainder
+// of the byte stream. An error is returned if the BodyMR in the header does
+// not match the body.
+func (e *EBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	r := newBlockReader(data)
+
+	n := new(EBlock)
+	n.Header.ChainID = r.hash("ChainID")
+	n.Header.BodyMR = r.hash("BodyMR")
+	n.Header.PrevKeyMR = r.hash("PrevKeyMR")
+	n.Header.PrevFullHash = r.hash("PrevFullHash")
+	n.Header.BlockSequenceNumber = int64(r.uint32("BlockSequenceNumber"))
+	n.Header.DBHeight = int64(r.uint32("DBHeight"))
+	count := int(r.uint32("EntryCount"))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if l := len(r.remaining()) / 32; count > l {
+		return nil, fmt.Errorf("%w: EntryCount is %d but there is only room for %d entries",
+			ErrBlockTruncated, count, l)
+	}
+
+	// the body is a list of Entry Hashes, each followed by the end of minute
+	// marker of the minute it was added in
+	body := make([][]byte, count)
+	pending := 0
+	for i := range body {
+		body[i] = r.next(32, fmt.Sprintf("Body[%d]", i))
+		if m, ok := minuteMarker(body[i]); ok {
+			if m < 1 || m > 10 {
+				return nil, fmt.Errorf("%w: Body[%d] is a marker for minute %d",
+					ErrInvalidBlock, i, m)
+			}
+			for j := pending; j < len(n.EntryList); j++ {
+				n.EntryList[j].Minute = m
+			}
+			pending = len(n.EntryList)
+			continue
+		}
+		n.EntryList = append(n.EntryList, EBEntry{EntryHash: hex.EncodeToString(body[i])})
+	}
+
+	bodyMR := merkleRoot(body)
+	if hex.EncodeToString(bodyMR) != n.Header.BodyMR {
+		return nil, fmt.Errorf("%w: header has %s, body has %x",
+			ErrBodyMRMismatch, n.Header.BodyMR, bodyMR)
+	}
+
+	headerHash := sha(data[:ebHeaderSize])
+	n.KeyMR = hex.EncodeToString(sha(append(headerHash, bodyMR...)))
+	n.EBHash = hex.EncodeToString(sha(data[:r.off]))
+
+	*e = *n
+	return r.remaining(), nil
+}
+
+// minuteMarker reports whether a 32 byte hash in the body of an Entry Block is
+// an end of minute marker, and for which minute.
+func minuteMarker(p []byte) (int, bool) {
+	for _, b := range p[:31] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return int(p[31]), true
+}
+
+// MarshalBinary encodes the Entry Block in the binary form used by factomd.
+// An end of minute marker follows the last EBEntry of each Minute.
+func (e *EBlock) MarshalBinary() ([]byte, error) {
+	body, err := e.marshalBody()
 	if err != nil {
 		return nil, err
 	}
-	if resp.Error != nil {
-		return nil, resp.Error
+	h, err := e.marshalHeader(len(body))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(h)
+	for _, v := range body {
+		buf.Write(v)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (e *EBlock) marshalHeader(count int) ([]byte, error) {
+	if e.Header.BodyMR == "" || e.Header.PrevFullHash == "" {
+		return nil, ErrEBlockNotBinary
 	}
 
+	buf := new(bytes.Buffer)
+
+	// 32 byte ChainID, BodyMR, PrevKeyMR and PrevFullHash
+	if err := writeHash(buf, e.Header.ChainID, "ChainID"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, e.Header.BodyMR, "BodyMR"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, e.Header.PrevKeyMR, "PrevKeyMR"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, e.Header.PrevFullHash, "PrevFullHash"); err != nil {
+		return nil, err
+	}
+
+	// 4 byte BlockSequenceNumber, DBHeight and EntryCount
+	binary.Write(buf, binary.BigEndian, uint32(e.Header.BlockSequenceNumber))
+	binary.Write(buf, binary.BigEndian, uint32(e.Header.DBHeight))
+	binary.Write(buf, binary.BigEndian, uint32(count))
+
+	return buf.Bytes(), nil
+}
+
+// marshalBody returns the Entry Hashes of the Entry Block with the end of
+// minute markers between them.
+func (e *EBlock) marshalBody() ([][]byte, error) {
+	var body [][]byte
+	for i, v := range e.EntryList {
+		p, err := hex.DecodeString(v.EntryHash)
+		if err != nil {
+			return nil, fmt.Errorf("EntryList[%d].EntryHash: %w", i, err)
+		}
+		if len(p) != 32 {
+			return nil, fmt.Errorf("EntryList[%d].EntryHash: expected 32 bytes, got %d", i, len(p))
+		}
+		body = append(body, p)
+
+		last := i+1 == len(e.EntryList) || e.EntryList[i+1].Minute != v.Minute
+		if v.Minute != 0 && last {
+			marker := make([]byte, 32)
+			marker[31] = byte(v.Minute)
+			body = append(body, marker)
+		}
+	}
+	return body, nil
+}
+
+// ComputeBodyMR computes the Merkle root of the Entry Hashes and end of minute
+// markers of the Entry Block.
+func (e *EBlock) ComputeBodyMR() ([]byte, error) {
+	body, err := e.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	return merkleRoot(body), nil
+}
+
+// ComputeKeyMR computes the Key Merkle Root of the Entry Block from its header
+// and the BodyMR of its body. It fails with ErrEBlockNotBinary for an Entry
+// Block from GetEBlock; use GetRaw and UnmarshalBinary to verify a KeyMR.
+func (e *EBlock) ComputeKeyMR() ([]byte, error) {
+	body, err := e.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	h, err := e.marshalHeader(len(body))
+	if err != nil {
+		return nil, err
+	}
+	return sha(append(sha(h), merkleRoot(body)...)), nil
+}
+
+// ComputeFullHash computes the hash of the whole binary Entry Block.
+func (e *EBlock) ComputeFullHash() ([]byte, error) {
+	p, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sha(p), nil
+}
+
+// GetEBlock requests an Entry Block from factomd by its Key Merkle Root
+func (c *Client) GetEBlock(ctx context.Context, keymr string) (*EBlock, error) {
+	params := keyMRRequest{KeyMR: keymr}
+	req := NewJSON2Request("entry-block", APICounter(), params)
 	eb := new(EBlock)
-	if err := json.Unmarshal(resp.JSONResult(), eb); err != nil {
+	if err := c.factomdCall(ctx, req, eb); err != nil {
 		return nil, err
 	}
 
 	return eb, nil
 }
 
-// GetAllEBlockEntries requests every Entry from a given Entry Block
-func GetAllEBlockEntries(keymr string) ([]*Entry, error) {
+// GetAllEBlockEntries requests every Entry from a given Entry Block. The
+// Entries are requested from factomd in batches; if factomd does not support
+// batch requests they are requested one at a time.
+func (c *Client) GetAllEBlockEntries(ctx context.Context, keymr string) ([]*Entry, error) {
 	es := make([]*Entry, 0)
 
-	eb, err := GetEBlock(keymr)
+	eb, err := c.GetEBlock(ctx, keymr)
 	if err != nil {
 		return es, err
 	}
 
+	for i := 0; i < len(eb.EntryList); i += entryBatchSize {
+		if err := ctx.Err(); err != nil {
+			return es, err
+		}
+		end := i + entryBatchSize
+		if end > len(eb.EntryList) {
+			end = len(eb.EntryList)
+		}
+		s, err := c.getEntryBatch(ctx, eb.EntryList[i:end])
+		if errors.Is(err, ErrBatchUnsupported) {
+			return c.getEBlockEntriesSequential(ctx, eb)
+		}
+		if err != nil {
+			return es, err
+		}
+		es = append(es, s...)
+	}
+
+	return es, nil
+}
+
+// entryBatchSize is the maximum number of Entries requested in a single batch.
+const entryBatchSize = 100
+
+// getEntryBatch requests the Entries for a list of EBlock Entries in a single
+// batch request.
+func (c *Client) getEntryBatch(ctx context.Context, list []EBEntry) ([]*Entry, error) {
+	reqs := make([]*JSON2Request, len(list))
+	for i, v := range list {
+		params := hashRequest{Hash: v.EntryHash}
+		reqs[i] = NewJSON2Request("entry", APICounter(), params)
+	}
+
+	resps, err := c.SendBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	es := make([]*Entry, 0, len(resps))
+	for _, resp := range resps {
+		if resp.Error != nil {
+			return es, resp.Error
+		}
+		e := new(Entry)
+		if err := json.Unmarshal(resp.JSONResult(), e); err != nil {
+			return es, err
+		}
+		es = append(es, e)
+	}
+
+	return es, nil
+}
+
+// getEBlockEntriesSequential requests every Entry from an Entry Block one at a
+// time.
+func (c *Client) getEBlockEntriesSequential(ctx context.Context, eb *EBlock) ([]*Entry, error) {
+	es := make([]*Entry, 0)
+
 	for _, v := range eb.EntryList {
-		e, err := GetEntry(v.EntryHash)
+		if err := ctx.Err(); err != nil {
+			return es, err
+		}
+		e, err := c.GetEntry(ctx, v.EntryHash)
 		if err != nil {
 			return es, err
 		}
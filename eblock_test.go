@@ -5,6 +5,11 @@
 package factom_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -65,4 +70,103 @@ EBEntry {
 		t.Errorf("expected:%s\nrecieved:%s", expectedResponse, response)
 	}
 	t.Log(response)
+
+	// the JSON form lacks the fields the KeyMR is computed from
+	if _, err := response.ComputeKeyMR(); !errors.Is(err, ErrEBlockNotBinary) {
+		t.Errorf("expected %v, got %v", ErrEBlockNotBinary, err)
+	}
+	if _, err := response.MarshalBinary(); !errors.Is(err, ErrEBlockNotBinary) {
+		t.Errorf("expected %v, got %v", ErrEBlockNotBinary, err)
+	}
+}
+
+func TestEBlockUnmarshalBinary(t *testing.T) {
+	sum := func(p ...[]byte) []byte {
+		h := sha256.Sum256(bytes.Join(p, nil))
+		return h[:]
+	}
+	hash := func(s string) []byte {
+		p, _ := hex.DecodeString(s)
+		return p
+	}
+	marker := func(m byte) []byte {
+		p := make([]byte, 32)
+		p[31] = m
+		return p
+	}
+
+	chainID := hash("df3ade9eec4b08d5379cc64270c30ea7315d8a8a1a69efe2b98a60ecdd69e604")
+	e1 := sum([]byte("entry 1"))
+	e2 := sum([]byte("entry 2"))
+	e3 := sum([]byte("entry 3"))
+
+	// entries 1 and 2 are in minute 1 and entry 3 in minute 4
+	body := [][]byte{e1, e2, marker(1), e3, marker(4)}
+	bodyMR := sum(sum(sum(e1, e2), sum(marker(1), e3)), sum(sum(marker(4), marker(4)), sum(marker(4), marker(4))))
+
+	header := new(bytes.Buffer)
+	header.Write(chainID)
+	header.Write(bodyMR)
+	header.Write(sum([]byte("prev keymr")))
+	header.Write(sum([]byte("prev full hash")))
+	binary.Write(header, binary.BigEndian, uint32(7))
+	binary.Write(header, binary.BigEndian, uint32(1000))
+	binary.Write(header, binary.BigEndian, uint32(len(body)))
+	raw := append(header.Bytes(), bytes.Join(body, nil)...)
+
+	eb := new(EBlock)
+	if err := eb.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if eb.Header.ChainID != hex.EncodeToString(chainID) || eb.Header.BlockSequenceNumber != 7 ||
+		eb.Header.DBHeight != 1000 || eb.Header.BodyMR != hex.EncodeToString(bodyMR) {
+		t.Errorf("unexpected header\n%s", eb)
+	}
+	if len(eb.EntryList) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(eb.EntryList))
+	}
+	for i, m := range []int{1, 1, 4} {
+		if eb.EntryList[i].Minute != m {
+			t.Errorf("expected entry %d in minute %d, got %d", i, m, eb.EntryList[i].Minute)
+		}
+	}
+	if eb.EntryList[2].EntryHash != hex.EncodeToString(e3) {
+		t.Errorf("unexpected entry hash %s", eb.EntryList[2].EntryHash)
+	}
+
+	keyMR := hex.EncodeToString(sum(sum(header.Bytes()), bodyMR))
+	if eb.KeyMR != keyMR {
+		t.Errorf("expected KeyMR %s, got %s", keyMR, eb.KeyMR)
+	}
+	if eb.EBHash != hex.EncodeToString(sum(raw)) {
+		t.Errorf("unexpected EBHash %s", eb.EBHash)
+	}
+
+	if b, err := eb.ComputeBodyMR(); err != nil || !bytes.Equal(b, bodyMR) {
+		t.Errorf("unexpected BodyMR %x %v", b, err)
+	}
+	if k, err := eb.ComputeKeyMR(); err != nil || hex.EncodeToString(k) != keyMR {
+		t.Errorf("unexpected KeyMR %x %v", k, err)
+	}
+	if f, err := eb.ComputeFullHash(); err != nil || hex.EncodeToString(f) != eb.EBHash {
+		t.Errorf("unexpected full hash %x %v", f, err)
+	}
+	if p, err := eb.MarshalBinary(); err != nil || !bytes.Equal(p, raw) {
+		t.Errorf("expected:%x\nrecieved:%x %v", raw, p, err)
+	}
+
+	bad := append([]byte(nil), raw...)
+	bad[len(bad)-33] ^= 1
+	if err := new(EBlock).UnmarshalBinary(bad); !errors.Is(err, ErrBodyMRMismatch) {
+		t.Errorf("expected %v, got %v", ErrBodyMRMismatch, err)
+	}
+	bad = append(bad[:len(bad)-1], 11)
+	if err := new(EBlock).UnmarshalBinary(bad); !errors.Is(err, ErrInvalidBlock) {
+		t.Errorf("expected %v, got %v", ErrInvalidBlock, err)
+	}
+	for _, l := range []int{0, 139, len(raw) - 1} {
+		if err := new(EBlock).UnmarshalBinary(raw[:l]); !errors.Is(err, ErrBlockTruncated) {
+			t.Errorf("%d bytes: expected %v, got %v", l, ErrBlockTruncated, err)
+		}
+	}
 }
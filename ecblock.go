@@ -5,6 +5,8 @@
 package factom
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
@@ -16,6 +18,7 @@ import (
 var (
 	ErrECIDUndefined   = errors.New("ECID type undefined")
 	ErrUnknownECBEntry = errors.New("Unknown Entry Credit Block Entry type")
+	ErrInvalidCommit   = errors.New("invalid commit message")
 )
 
 // ECID defines the type of an Entry Credit Block Entry
@@ -159,6 +162,245 @@ func (e *ECBlock) UnmarshalJSON(js []byte) error {
 	return nil
 }
 
+// ecChainID is the ChainID of the Entry Credit Blocks.
+const ecChainID = "000000000000000000000000000000000000000000000000000000000000000c"
+
+// Sizes of the binary commit messages.
+const (
+	ecChainCommitSize = 200
+	ecEntryCommitSize = 136
+)
+
+// UnmarshalBinary decodes a binary Entry Credit Block, such as the raw data
+// returned by GetRaw. The HeaderHash and FullHash are computed from the
+// decoded block.
+func (e *ECBlock) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData decodes a binary Entry Credit Block and returns the
+// remainder of the byte stream. An error is returned if the BodyHash in the
+// header does not match the body.
+func (e *ECBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	r := newBlockReader(data)
+
+	n := new(ECBlock)
+	if id := r.hash("ECChainID"); r.err == nil && id != ecChainID {
+		return nil, fmt.Errorf("%w: ECChainID is %s", ErrInvalidBlock, id)
+	}
+	n.Header.BodyHash = r.hash("BodyHash")
+	n.Header.PrevHeaderHash = r.hash("PrevHeaderHash")
+	n.Header.PrevFullHash = r.hash("PrevFullHash")
+	n.Header.DBHeight = int64(r.uint32("DBHeight"))
+	n.Header.HeaderExpansionArea = r.bytes(int(r.varint("HeaderExpansionSize")), "HeaderExpansionArea")
+	count := r.uint64("ObjectCount")
+	size := r.uint64("BodySize")
+	if r.err != nil {
+		return nil, r.err
+	}
+	if l := uint64(len(r.remaining())); size > l {
+		return nil, fmt.Errorf("%w: BodySize is %d bytes but %d remain",
+			ErrBlockTruncated, size, l)
+	}
+	headerSize := r.off
+
+	body := r.next(int(size), "Body")
+	if h := hex.EncodeToString(sha(body)); h != n.Header.BodyHash {
+		return nil, fmt.Errorf("%w: header has %s, body has %s",
+			ErrBodyHashMismatch, n.Header.BodyHash, h)
+	}
+	br := newBlockReader(body)
+	for i := uint64(0); i < count; i++ {
+		v, err := unmarshalECBEntry(br)
+		if err != nil {
+			return nil, fmt.Errorf("Entries[%d]: %w", i, err)
+		}
+		n.Entries = append(n.Entries, v)
+	}
+	if l := len(br.remaining()); l > 0 {
+		return nil, fmt.Errorf("%w: %d bytes of the body follow the last of %d Entries",
+			ErrInvalidBlock, l, count)
+	}
+
+	n.HeaderHash = hex.EncodeToString(sha(data[:headerSize]))
+	n.FullHash = hex.EncodeToString(sha(data[:r.off]))
+
+	*e = *n
+	return r.remaining(), nil
+}
+
+// unmarshalECBEntry reads an ECBEntry from the body of an Entry Credit Block.
+func unmarshalECBEntry(r *blockReader) (ECBEntry, error) {
+	id := ECID(r.uint8("ECID"))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var v ECBEntry
+	switch id {
+	case ECIDServerIndexNumber:
+		v = &ECServerIndexNumber{
+			ServerIndexNumber: int(r.uint8("ServerIndexNumber")),
+		}
+	case ECIDMinuteNumber:
+		v = &ECMinuteNumber{
+			Number: int(r.uint8("MinuteNumber")),
+		}
+	case ECIDChainCommit:
+		c := new(ECChainCommit)
+		if p := r.next(ecChainCommitSize, "ChainCommit"); p != nil {
+			if err := c.UnmarshalBinary(p); err != nil {
+				return nil, err
+			}
+		}
+		v = c
+	case ECIDEntryCommit:
+		c := new(ECEntryCommit)
+		if p := r.next(ecEntryCommitSize, "EntryCommit"); p != nil {
+			if err := c.UnmarshalBinary(p); err != nil {
+				return nil, err
+			}
+		}
+		v = c
+	case ECIDBalanceIncrease:
+		v = &ECBalanceIncrease{
+			ECPubKey: r.hash("ECPubKey"),
+			TXID:     r.hash("TXID"),
+			Index:    r.varint("Index"),
+			NumEC:    r.varint("NumEC"),
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownECBEntry, id)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return v, nil
+}
+
+// MarshalBinary encodes the Entry Credit Block in the binary form used by
+// factomd.
+func (e *ECBlock) MarshalBinary() ([]byte, error) {
+	body, err := e.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	h, err := e.marshalHeader(len(body))
+	if err != nil {
+		return nil, err
+	}
+	return append(h, body...), nil
+}
+
+func (e *ECBlock) marshalHeader(bodySize int) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// 32 byte ECChainID, BodyHash, PrevHeaderHash and PrevFullHash
+	writeHash(buf, ecChainID, "ECChainID")
+	if err := writeHash(buf, e.Header.BodyHash, "BodyHash"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, e.Header.PrevHeaderHash, "PrevHeaderHash"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, e.Header.PrevFullHash, "PrevFullHash"); err != nil {
+		return nil, err
+	}
+
+	// 4 byte DBHeight
+	binary.Write(buf, binary.BigEndian, uint32(e.Header.DBHeight))
+
+	// varint HeaderExpansionSize and HeaderExpansionArea
+	writeVarInt(buf, uint64(len(e.Header.HeaderExpansionArea)))
+	buf.Write(e.Header.HeaderExpansionArea)
+
+	// 8 byte ObjectCount and BodySize
+	binary.Write(buf, binary.BigEndian, uint64(len(e.Entries)))
+	binary.Write(buf, binary.BigEndian, uint64(bodySize))
+
+	return buf.Bytes(), nil
+}
+
+func (e *ECBlock) marshalBody() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for i, v := range e.Entries {
+		if err := marshalECBEntry(buf, v); err != nil {
+			return nil, fmt.Errorf("Entries[%d]: %w", i, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalECBEntry writes the ECID and data of an ECBEntry.
+func marshalECBEntry(buf *bytes.Buffer, v ECBEntry) error {
+	buf.WriteByte(byte(v.Type()))
+
+	switch v := v.(type) {
+	case *ECServerIndexNumber:
+		buf.WriteByte(byte(v.ServerIndexNumber))
+	case *ECMinuteNumber:
+		buf.WriteByte(byte(v.Number))
+	case *ECChainCommit:
+		p, err := v.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		buf.Write(p)
+	case *ECEntryCommit:
+		p, err := v.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		buf.Write(p)
+	case *ECBalanceIncrease:
+		if err := writeHash(buf, v.ECPubKey, "ECPubKey"); err != nil {
+			return err
+		}
+		if err := writeHash(buf, v.TXID, "TXID"); err != nil {
+			return err
+		}
+		writeVarInt(buf, v.Index)
+		writeVarInt(buf, v.NumEC)
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownECBEntry, v.Type())
+	}
+	return nil
+}
+
+// ComputeBodyHash computes the hash of the binary body of the Entry Credit
+// Block.
+func (e *ECBlock) ComputeBodyHash() ([]byte, error) {
+	body, err := e.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	return sha(body), nil
+}
+
+// ComputeHeaderHash computes the hash of the binary header of the Entry Credit
+// Block, which it is referenced by in the Directory Block.
+func (e *ECBlock) ComputeHeaderHash() ([]byte, error) {
+	body, err := e.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	h, err := e.marshalHeader(len(body))
+	if err != nil {
+		return nil, err
+	}
+	return sha(h), nil
+}
+
+// ComputeFullHash computes the hash of the whole binary Entry Credit Block.
+func (e *ECBlock) ComputeFullHash() ([]byte, error) {
+	p, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sha(p), nil
+}
+
 // an ECBEntry is an individual member of the Entry Credit Block.
 type ECBEntry interface {
 	Type() ECID
@@ -243,6 +485,68 @@ func (c *ECChainCommit) UnmarshalJSON(js []byte) error {
 	return nil
 }
 
+// UnmarshalBinary decodes a binary Chain Commit; the message of a
+// commit-chain request.
+func (c *ECChainCommit) UnmarshalBinary(data []byte) error {
+	if len(data) != ecChainCommitSize {
+		return fmt.Errorf("%w: ChainCommit is %d bytes, expected %d",
+			ErrInvalidCommit, len(data), ecChainCommitSize)
+	}
+	r := newBlockReader(data)
+
+	c.Version = int(r.uint8("Version"))
+	c.MilliTime = int64(uint48(r.next(6, "MilliTime")))
+	c.ChainIDHash = r.hash("ChainIDHash")
+	c.Weld = r.hash("Weld")
+	c.EntryHash = r.hash("EntryHash")
+	c.Credits = int(r.uint8("Credits"))
+	c.ECPubKey = r.hash("ECPubKey")
+	c.Sig = r.hex(64, "Sig")
+
+	return r.err
+}
+
+// MarshalBinary encodes the Chain Commit in the binary form of a commit-chain
+// message.
+func (c *ECChainCommit) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	buf.WriteByte(byte(c.Version))
+	buf.Write(putUint48(uint64(c.MilliTime)))
+	if err := writeHash(buf, c.ChainIDHash, "ChainIDHash"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, c.Weld, "Weld"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, c.EntryHash, "EntryHash"); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(byte(c.Credits))
+	if err := writeHash(buf, c.ECPubKey, "ECPubKey"); err != nil {
+		return nil, err
+	}
+	if err := writeHex(buf, c.Sig, 64, "Sig"); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseChainCommit decodes the hex message of a commit-chain request, such as
+// the one built by ComposeChainCommit.
+func ParseChainCommit(message string) (*ECChainCommit, error) {
+	p, err := hex.DecodeString(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCommit, err)
+	}
+	c := new(ECChainCommit)
+	if err := c.UnmarshalBinary(p); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func (c *ECChainCommit) Type() ECID {
 	return ECIDChainCommit
 }
@@ -308,6 +612,60 @@ func (e *ECEntryCommit) UnmarshalJSON(js []byte) error {
 	return nil
 }
 
+// UnmarshalBinary decodes a binary Entry Commit; the message of a
+// commit-entry request.
+func (e *ECEntryCommit) UnmarshalBinary(data []byte) error {
+	if len(data) != ecEntryCommitSize {
+		return fmt.Errorf("%w: EntryCommit is %d bytes, expected %d",
+			ErrInvalidCommit, len(data), ecEntryCommitSize)
+	}
+	r := newBlockReader(data)
+
+	e.Version = int(r.uint8("Version"))
+	e.MilliTime = int64(uint48(r.next(6, "MilliTime")))
+	e.EntryHash = r.hash("EntryHash")
+	e.Credits = int(r.uint8("Credits"))
+	e.ECPubKey = r.hash("ECPubKey")
+	e.Sig = r.hex(64, "Sig")
+
+	return r.err
+}
+
+// MarshalBinary encodes the Entry Commit in the binary form of a commit-entry
+// message.
+func (e *ECEntryCommit) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	buf.WriteByte(byte(e.Version))
+	buf.Write(putUint48(uint64(e.MilliTime)))
+	if err := writeHash(buf, e.EntryHash, "EntryHash"); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(byte(e.Credits))
+	if err := writeHash(buf, e.ECPubKey, "ECPubKey"); err != nil {
+		return nil, err
+	}
+	if err := writeHex(buf, e.Sig, 64, "Sig"); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseEntryCommit decodes the hex message of a commit-entry request, such as
+// the one built by EntryCommitMessage.
+func ParseEntryCommit(message string) (*ECEntryCommit, error) {
+	p, err := hex.DecodeString(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCommit, err)
+	}
+	e := new(ECEntryCommit)
+	if err := e.UnmarshalBinary(p); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 func (e *ECEntryCommit) Type() ECID {
 	return ECIDEntryCommit
 }
@@ -351,23 +709,14 @@ func (e *ECBalanceIncrease) String() string {
 }
 
 // GetECBlock requests a specified Entry Credit Block from the factomd API
-func GetECBlock(keymr string) (ecblock *ECBlock, err error) {
+func (c *Client) GetECBlock(ctx context.Context, keymr string) (ecblock *ECBlock, err error) {
 	params := keyMRRequest{KeyMR: keymr, NoRaw: true}
 	req := NewJSON2Request("entrycredit-block", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	// create a wraper construct for the ECBlock API return
 	wrap := new(struct {
 		ECBlock *ECBlock `json:"ecblock"`
 	})
-	err = json.Unmarshal(resp.JSONResult(), wrap)
-	if err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
@@ -375,21 +724,13 @@ func GetECBlock(keymr string) (ecblock *ECBlock, err error) {
 }
 
 // GetECBlockByHeight request an Entry Credit Block of a given height
-func GetECBlockByHeight(height int64) (ecblock *ECBlock, err error) {
+func (c *Client) GetECBlockByHeight(ctx context.Context, height int64) (ecblock *ECBlock, err error) {
 	params := heightRequest{Height: height, NoRaw: true}
 	req := NewJSON2Request("ecblock-by-height", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	wrap := new(struct {
 		ECBlock *ECBlock `json:"ecblock"`
 	})
-	if err = json.Unmarshal(resp.JSONResult(), wrap); err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
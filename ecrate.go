@@ -5,27 +5,19 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 )
 
 // GetECRate returns the current conversion rate cost in factoshis
 // (Factoid^(-1e8)) of purchasing Entry Credits.
-func GetECRate() (uint64, error) {
+func (c *Client) GetECRate(ctx context.Context) (uint64, error) {
 	type rateResponse struct {
 		Rate uint64 `json:"rate"`
 	}
 
 	req := NewJSON2Request("entry-credit-rate", APICounter(), nil)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return 0, err
-	}
-	if resp.Error != nil {
-		return 0, resp.Error
-	}
-
 	rate := new(rateResponse)
-	if err := json.Unmarshal(resp.JSONResult(), rate); err != nil {
+	if err := c.factomdCall(ctx, req, rate); err != nil {
 		return 0, err
 	}
 
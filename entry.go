@@ -6,12 +6,74 @@ package factom
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 )
 
+// Sizes of the binary Entry format.
+const (
+	// EntryHeaderSize is the size of the version, ChainID and ExtIDs size
+	// that start every Entry.
+	EntryHeaderSize = 35
+	// MaxEntryPayloadSize is the largest size of the ExtIDs and Content of an
+	// Entry together.
+	MaxEntryPayloadSize = 10240
+)
+
+var (
+	ErrEntryTruncated = errors.New("Entry data truncated")
+	ErrEntryTooLarge  = errors.New("Entry cannot be larger than 10KB")
+	ErrInvalidEntry   = errors.New("invalid Entry data")
+
+	ErrChainIDFormat   = errors.New("ChainID must be 32 bytes of hex")
+	ErrExtIDTooLarge   = errors.New("ExtID does not fit in the Entry payload")
+	ErrExtIDsTooLarge  = errors.New("ExtIDs do not fit in the Entry payload")
+	ErrChainNoExtIDs   = errors.New("First Entry of a Chain must have at least one ExtID")
+	ErrChainIDMismatch = errors.New("ChainID does not match the First Entry ExtIDs")
+	ErrNoFirstEntry    = errors.New("Chain has no First Entry")
+)
+
+// An EntryError is a protocol rule broken by a field of an Entry or Chain.
+type EntryError struct {
+	Field string
+	Err   error
+}
+
+func (e *EntryError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every EntryError found by Entry.Validate or
+// Chain.Validate. errors.Is reports whether any of them match.
+type ValidationError []*EntryError
+
+func (v ValidationError) Error() string {
+	s := make([]string, len(v))
+	for i, e := range v {
+		s[i] = e.Error()
+	}
+	return "invalid Entry: " + strings.Join(s, "; ")
+}
+
+func (v ValidationError) Is(target error) bool {
+	for _, e := range v {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
 type Entry struct {
 	ChainID string   `json:"chainid"`
 	ExtIDs  [][]byte `json:"extids"`
@@ -72,7 +134,10 @@ func (e *Entry) MarshalBinary() ([]byte, error) {
 	}
 
 	// 2 byte size of extids
-	if err := binary.Write(buf, binary.BigEndian, int16(len(ids))); err != nil {
+	if len(ids) > math.MaxUint16 {
+		return buf.Bytes(), fmt.Errorf("%w: %d bytes", ErrExtIDsTooLarge, len(ids))
+	}
+	if err := binary.Write(buf, binary.BigEndian, uint16(len(ids))); err != nil {
 		return buf.Bytes(), err
 	}
 
@@ -90,9 +155,14 @@ func (e *Entry) MarshalBinary() ([]byte, error) {
 func (e *Entry) MarshalExtIDsBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
 
-	for _, v := range e.ExtIDs {
+	for i, v := range e.ExtIDs {
 		// 2 byte length of extid
-		binary.Write(buf, binary.BigEndian, int16(len(v)))
+		if len(v) > math.MaxUint16 {
+			return nil, fmt.Errorf("%w: ExtID %d is %d bytes", ErrExtIDTooLarge, i, len(v))
+		}
+		if err := binary.Write(buf, binary.BigEndian, uint16(len(v))); err != nil {
+			return nil, err
+		}
 		// extid
 		buf.Write(v)
 	}
@@ -100,6 +170,126 @@ func (e *Entry) MarshalExtIDsBinary() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Validate checks the Entry against the limits of the Factom protocol. The
+// returned ValidationError lists every violation found.
+func (e *Entry) Validate() error {
+	var v ValidationError
+	if err := validChainID(e.ChainID); err != nil {
+		v = append(v, &EntryError{"ChainID", err})
+	}
+	v = append(v, e.validatePayload("")...)
+	if len(v) > 0 {
+		return v
+	}
+	return nil
+}
+
+// validatePayload checks the sizes of the ExtIDs and Content. prefix is
+// prepended to the Field of the errors.
+func (e *Entry) validatePayload(prefix string) ValidationError {
+	var v ValidationError
+
+	size := 0
+	for i, id := range e.ExtIDs {
+		// each ExtID is prefixed with its 2 byte length
+		if l := len(id) + 2; l > MaxEntryPayloadSize {
+			v = append(v, &EntryError{
+				fmt.Sprintf("%sExtIDs[%d]", prefix, i),
+				fmt.Errorf("%w: %d bytes", ErrExtIDTooLarge, len(id)),
+			})
+		}
+		size += len(id) + 2
+	}
+	if size > MaxEntryPayloadSize {
+		v = append(v, &EntryError{
+			prefix + "ExtIDs",
+			fmt.Errorf("%w: %d bytes", ErrExtIDsTooLarge, size),
+		})
+	} else if l := size + len(e.Content); l > MaxEntryPayloadSize {
+		v = append(v, &EntryError{
+			prefix + "Content",
+			fmt.Errorf("%w: payload is %d bytes", ErrEntryTooLarge, l),
+		})
+	}
+
+	return v
+}
+
+// validChainID checks that a ChainID is 32 bytes of hex.
+func validChainID(chainID string) error {
+	if len(chainID) != 64 {
+		return fmt.Errorf("%w: %d characters", ErrChainIDFormat, len(chainID))
+	}
+	if _, err := hex.DecodeString(chainID); err != nil {
+		return fmt.Errorf("%w: %s", ErrChainIDFormat, err)
+	}
+	return nil
+}
+
+func (e *Entry) UnmarshalBinary(data []byte) error {
+	_, err := e.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData reads an Entry from a byte stream and returns the
+// remainder of the byte stream. Entries are not length prefixed; the Content
+// is the rest of the data after the ExtIDs, so the remainder is always empty.
+func (e *Entry) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	if len(data) < EntryHeaderSize {
+		return nil, fmt.Errorf("%w: %d bytes is shorter than the %d byte header",
+			ErrEntryTruncated, len(data), EntryHeaderSize)
+	}
+	if l := len(data) - EntryHeaderSize; l > MaxEntryPayloadSize {
+		return nil, fmt.Errorf("%w: payload is %d bytes", ErrEntryTooLarge, l)
+	}
+
+	// 1 byte Version
+	if data[0] != 0 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEntry, data[0])
+	}
+
+	// 32 byte chainid
+	chainID := hex.EncodeToString(data[1:33])
+
+	// 2 byte size of extids
+	size := int(binary.BigEndian.Uint16(data[33:35]))
+	body := data[EntryHeaderSize:]
+	if size > len(body) {
+		return nil, fmt.Errorf("%w: ExtIDs size is %d bytes but only %d bytes follow the header",
+			ErrEntryTruncated, size, len(body))
+	}
+
+	// ExtIDs
+	var extIDs [][]byte
+	ids := body[:size]
+	for i := 0; len(ids) > 0; i++ {
+		if len(ids) < 2 {
+			return nil, fmt.Errorf("%w: ExtID %d length is cut off at the end of the ExtIDs",
+				ErrInvalidEntry, i)
+		}
+		l := int(binary.BigEndian.Uint16(ids[:2]))
+		ids = ids[2:]
+		if l > len(ids) {
+			return nil, fmt.Errorf("%w: ExtID %d is %d bytes but only %d bytes of ExtIDs remain",
+				ErrInvalidEntry, i, l, len(ids))
+		}
+		id := make([]byte, l)
+		copy(id, ids[:l])
+		extIDs = append(extIDs, id)
+		ids = ids[l:]
+	}
+
+	// Content
+	content := make([]byte, len(body)-size)
+	copy(content, body[size:])
+
+	e.ChainID = chainID
+	e.ExtIDs = extIDs
+	e.Content = content
+
+	return data[len(data):], nil
+}
+
 func (e *Entry) MarshalJSON() ([]byte, error) {
 	type js struct {
 		ChainID string   `json:"chainid"`
@@ -206,8 +396,11 @@ func EntryCommitMessage(e *Entry, ec *ECAddress) (*bytes.Buffer, error) {
 
 // ComposeEntryCommit creates a JSON2Request to commit a new Entry via the
 // factomd web api. The request includes the marshaled MessageRequest with the
-// Entry Credit Signature.
+// Entry Credit Signature. The Entry is checked with Entry.Validate first.
 func ComposeEntryCommit(e *Entry, ec *ECAddress) (*JSON2Request, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
 	b, err := EntryCommitMessage(e, ec)
 	if err != nil {
 		return nil, err
@@ -234,7 +427,7 @@ func ComposeEntryReveal(e *Entry) (*JSON2Request, error) {
 // CommitEntry sends the signed Entry Hash and the Entry Credit public key to
 // the factom network. Once the payment is verified and the network is commited
 // to publishing the Entry it may be published with a call to RevealEntry.
-func CommitEntry(e *Entry, ec *ECAddress) (string, error) {
+func (c *Client) CommitEntry(ctx context.Context, e *Entry, ec *ECAddress) (string, error) {
 	type commitResponse struct {
 		Message string `json:"message"`
 		TxID    string `json:"txid"`
@@ -245,16 +438,8 @@ func CommitEntry(e *Entry, ec *ECAddress) (string, error) {
 		return "", err
 	}
 
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.Error != nil {
-		return "", resp.Error
-	}
 	r := new(commitResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := c.factomdCall(ctx, req, r); err != nil {
 		return "", err
 	}
 
@@ -263,7 +448,7 @@ func CommitEntry(e *Entry, ec *ECAddress) (string, error) {
 
 // RevealEntrysends the Entry data to the factom network to create an Entry that
 // has previously been commited.
-func RevealEntry(e *Entry) (string, error) {
+func (c *Client) RevealEntry(ctx context.Context, e *Entry) (string, error) {
 	type revealResponse struct {
 		Message string `json:"message"`
 		Entry   string `json:"entryhash"`
@@ -274,35 +459,19 @@ func RevealEntry(e *Entry) (string, error) {
 		return "", err
 	}
 
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != nil {
-		return "", resp.Error
-	}
-
 	r := new(revealResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := c.factomdCall(ctx, req, r); err != nil {
 		return "", err
 	}
 	return r.Entry, nil
 }
 
 // GetEntry requests an Entry from the factomd API by its Entry Hash
-func GetEntry(hash string) (*Entry, error) {
+func (c *Client) GetEntry(ctx context.Context, hash string) (*Entry, error) {
 	params := hashRequest{Hash: hash}
 	req := NewJSON2Request("entry", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	e := new(Entry)
-	if err := json.Unmarshal(resp.JSONResult(), e); err != nil {
+	if err := c.factomdCall(ctx, req, e); err != nil {
 		return nil, err
 	}
 
@@ -317,19 +486,10 @@ func GetEntry(hash string) (*Entry, error) {
 // Where entries in VM# are ordered inside and Unconfirmed are random.
 // Unconfirmed entries are entry reveals with a status of NotConfirmed
 // and only exist on the node, not the rest of the network.
-func GetPendingEntries() ([]PendingEntry, error) {
+func (c *Client) GetPendingEntries(ctx context.Context) ([]PendingEntry, error) {
 	req := NewJSON2Request("pending-entries", APICounter(), nil)
-	resp, err := factomdRequest(req)
-
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, err
-	}
-
 	pending := make([]PendingEntry, 0)
-	if err := json.Unmarshal(resp.JSONResult(), &pending); err != nil {
+	if err := c.factomdCall(ctx, req, &pending); err != nil {
 		return nil, err
 	}
 
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -96,6 +97,146 @@ func TestMarshalBinary(t *testing.T) {
 	t.Logf("%x", result)
 }
 
+func TestUnmarshalBinary(t *testing.T) {
+	raw, _ := hex.DecodeString("005a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be7090690035001854686973206973207468652066697273742065787469642e00195468697320697320746865207365636f6e642065787469642e546869732069732061207465737420456e7472792e")
+
+	ent := new(Entry)
+	if err := ent.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if ent.ChainID != "5a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be709069" {
+		t.Errorf("unexpected ChainID %s", ent.ChainID)
+	}
+	if len(ent.ExtIDs) != 2 || string(ent.ExtIDs[1]) != "This is the second extid." {
+		t.Errorf("unexpected ExtIDs %q", ent.ExtIDs)
+	}
+	if string(ent.Content) != "This is a test Entry." {
+		t.Errorf("unexpected Content %q", ent.Content)
+	}
+
+	// the decoded entry should marshal and hash to the original bytes
+	result, _ := ent.MarshalBinary()
+	if !bytes.Equal(result, raw) {
+		t.Errorf("expected:%x\nrecieved:%x", raw, result)
+	}
+	h := ent.Hash()
+	ent2 := &Entry{
+		ChainID: "5a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be709069",
+		ExtIDs:  [][]byte{[]byte("This is the first extid."), []byte("This is the second extid.")},
+		Content: []byte("This is a test Entry."),
+	}
+	if !bytes.Equal(h, ent2.Hash()) {
+		t.Errorf("expected hash %x, got %x", ent2.Hash(), h)
+	}
+
+	// empty ExtIDs and Content
+	empty := &Entry{ChainID: ent.ChainID}
+	b, _ := empty.MarshalBinary()
+	if err := ent.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if len(ent.ExtIDs) != 0 || len(ent.Content) != 0 {
+		t.Errorf("expected an empty entry, got %s", ent)
+	}
+	if r, _ := ent.MarshalBinary(); !bytes.Equal(r, b) {
+		t.Errorf("expected:%x\nrecieved:%x", b, r)
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	raw, _ := hex.DecodeString("005a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be7090690035001854686973206973207468652066697273742065787469642e00195468697320697320746865207365636f6e642065787469642e546869732069732061207465737420456e7472792e")
+
+	badVersion := append([]byte(nil), raw...)
+	badVersion[0] = 1
+
+	badExtID := append([]byte(nil), raw...)
+	badExtID[36] = 0x30 // first ExtID runs past the ExtIDs
+
+	oddExtIDs := append([]byte(nil), raw...)
+	oddExtIDs[34] = 0x1b // ExtIDs end in the middle of a length
+
+	tooLarge := make([]byte, 35+10241)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrEntryTruncated},
+		{"short header", raw[:34], ErrEntryTruncated},
+		{"short ExtIDs", raw[:60], ErrEntryTruncated},
+		{"bad version", badVersion, ErrInvalidEntry},
+		{"bad ExtID length", badExtID, ErrInvalidEntry},
+		{"cut ExtID length", oddExtIDs, ErrInvalidEntry},
+		{"too large", tooLarge, ErrEntryTooLarge},
+	}
+	for _, test := range tests {
+		ent := new(Entry)
+		if err := ent.UnmarshalBinary(test.data); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+
+	// the largest entry is accepted
+	max := make([]byte, 35+10240)
+	if err := new(Entry).UnmarshalBinary(max); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEntryValidate(t *testing.T) {
+	ent := NewEntryFromStrings(
+		"5a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be709069",
+		"This is a test Entry.",
+		"This is the first extid.",
+	)
+	if err := ent.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// an entry at the size limit is valid
+	ent.Content = make([]byte, 10240-26)
+	if err := ent.Validate(); err != nil {
+		t.Error(err)
+	}
+	ent.Content = append(ent.Content, 0)
+	if err := ent.Validate(); !errors.Is(err, ErrEntryTooLarge) {
+		t.Errorf("expected %v, got %v", ErrEntryTooLarge, err)
+	}
+
+	bad := &Entry{
+		ChainID: "not a chainid",
+		ExtIDs:  [][]byte{[]byte("ok"), make([]byte, 10239), make([]byte, 10)},
+	}
+	err := bad.Validate()
+	v, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	fields := make([]string, len(v))
+	for i, e := range v {
+		fields[i] = e.Field
+	}
+	if fmt.Sprint(fields) != "[ChainID ExtIDs[1] ExtIDs]" {
+		t.Errorf("unexpected violations %v", err)
+	}
+	for _, target := range []error{ErrChainIDFormat, ErrExtIDTooLarge, ErrExtIDsTooLarge} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected %v in %v", target, err)
+		}
+	}
+
+	ecAddr, _ := GetECAddress("Es2Rf7iM6PdsqfYCo3D1tnAR65SkLENyWJG1deUzpRMQmbh9F3eG")
+	if _, err := ComposeEntryCommit(bad, ecAddr); !errors.Is(err, ErrChainIDFormat) {
+		t.Errorf("expected ComposeEntryCommit to fail with %v, got %v", ErrChainIDFormat, err)
+	}
+
+	// ExtIDs that cannot be encoded fail to marshal instead of being truncated
+	if _, err := (&Entry{ChainID: ent.ChainID, ExtIDs: [][]byte{make([]byte, 70000)}}).MarshalBinary(); !errors.Is(err, ErrExtIDTooLarge) {
+		t.Errorf("expected %v, got %v", ErrExtIDTooLarge, err)
+	}
+}
+
 func TestNewEntry(t *testing.T) {
 	expected, err := hex.DecodeString("005a402200c5cf278e47905ce52d7d64529a0291829a7bd230072c5468be7090690035001854686973206973207468652066697273742065787469642e00195468697320697320746865207365636f6e642065787469642e546869732069732061207465737420456e7472792e")
 	if err != nil {
@@ -5,8 +5,13 @@
 package factom
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -21,6 +26,8 @@ type FBlock struct {
 	DBHeight        int64            `json:"dbheight"`        // Directory Block height
 	Transactions    []*FBTransaction `json:"transactions"`    // The transactions inside the block
 
+	HeaderExpansionArea string `json:"headerexpansionarea,omitempty"`
+
 	ChainID     string `json:"chainid,omitempty"`
 	KeyMR       string `json:"keymr,omitempty"`
 	LedgerKeyMR string `json:"ledgerkeymr,omitempty"`
@@ -29,6 +36,9 @@ type FBlock struct {
 // FBTransactions represents a single and valid transaction contained inside
 // of an FBlock.
 // The data has been rearranged from the raw json response to make it easier to work with.
+//
+// Minute is the minute of the block, 1 to 10, the transaction was added in. It
+// is only known for Factoid Blocks decoded from binary.
 type FBTransaction struct {
 	TxID        string                     `json:"txid"` // hex string
 	BlockHeight int64                      `json:"blockheight"`
@@ -36,6 +46,7 @@ type FBTransaction struct {
 	Inputs      []SignedTransactionAddress `json:"inputs"`
 	Outputs     []TransactionAddress       `json:"outputs"`
 	ECOutputs   []TransactionAddress       `json:"outecs"`
+	Minute      int                        `json:"minute,omitempty"`
 }
 
 // TransactionAddress holds the relevant data for either an input or an output.
@@ -67,6 +78,7 @@ type rawFBTransaction struct {
 	ECOutputs      []TransactionAddress `json:"outecs"`
 	RCDs           []string             `json:"rcds"`
 	SigBlocks      []rawSigBlock        `json:"sigblocks"`
+	Minute         int                  `json:"minute,omitempty"`
 }
 type rawSigBlock struct {
 	Signatures []string `json:"signatures"`
@@ -76,12 +88,13 @@ func (t *FBTransaction) MarshalJSON() ([]byte, error) {
 	txReq := &rawFBTransaction{
 		TxID:           t.TxID,
 		BlockHeight:    t.BlockHeight,
-		MilliTimestamp: t.Timestamp.UnixNano()/1e6 + (t.Timestamp.UnixNano()/1e3)%1e3,
+		MilliTimestamp: fbMilliTimestamp(t.Timestamp),
 		Outputs:        t.Outputs,
 		ECOutputs:      t.ECOutputs,
 		Inputs:         make([]TransactionAddress, 0, len(t.Inputs)),
 		RCDs:           make([]string, 0, len(t.Inputs)),
 		SigBlocks:      make([]rawSigBlock, 0, len(t.Inputs)),
+		Minute:         t.Minute,
 	}
 
 	for _, in := range t.Inputs {
@@ -101,8 +114,8 @@ func (t *FBTransaction) UnmarshalJSON(data []byte) error {
 	}
 
 	t.BlockHeight = txResp.BlockHeight
-	// the bug in the nanosecond conversion is intentional to stay consistent with factomd
-	t.Timestamp = time.Unix(txResp.MilliTimestamp/1e3, (txResp.MilliTimestamp%1e3)*1e3)
+	t.Timestamp = fbTimestamp(txResp.MilliTimestamp)
+	t.Minute = txResp.Minute
 	t.Outputs = txResp.Outputs
 	t.ECOutputs = txResp.ECOutputs
 	t.TxID = txResp.TxID
@@ -174,24 +187,453 @@ func (f *FBlock) String() string {
 	return s
 }
 
-// GetFBlock requests a specified Factoid Block from factomd by its keymr
-func GetFBlock(keymr string) (fblock *FBlock, err error) {
-	params := keyMRRequest{KeyMR: keymr, NoRaw: true}
-	req := NewJSON2Request("factoid-block", APICounter(), params)
-	resp, err := factomdRequest(req)
+// factoidChainID is the ChainID of the Factoid Blocks.
+const factoidChainID = "000000000000000000000000000000000000000000000000000000000000000f"
+
+// Sizes of the binary transaction fields.
+const (
+	fbTransactionVersion = 2
+	rcd1Size             = 33
+	signatureSize        = 64
+)
+
+// fbTimestamp converts a factomd millisecond timestamp to a time.Time.
+func fbTimestamp(ms int64) time.Time {
+	// the bug in the nanosecond conversion is intentional to stay consistent with factomd
+	return time.Unix(ms/1e3, (ms%1e3)*1e3)
+}
+
+// fbMilliTimestamp is the inverse of fbTimestamp.
+func fbMilliTimestamp(t time.Time) int64 {
+	return t.UnixNano()/1e6 + (t.UnixNano()/1e3)%1e3
+}
+
+// UnmarshalBinary decodes a binary Factoid transaction. The TxID is computed
+// from the decoded transaction.
+func (t *FBTransaction) UnmarshalBinary(data []byte) error {
+	_, err := t.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData decodes a binary Factoid transaction and returns the
+// remainder of the byte stream.
+func (t *FBTransaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	r := newBlockReader(data)
+	n, err := unmarshalFBTransaction(r)
 	if err != nil {
-		return
+		return nil, err
 	}
-	if resp.Error != nil {
-		return nil, resp.Error
+	*t = *n
+	return r.remaining(), nil
+}
+
+func unmarshalFBTransaction(r *blockReader) (*FBTransaction, error) {
+	start := r.off
+
+	t := new(FBTransaction)
+	if v := r.varint("Version"); r.err == nil && v != fbTransactionVersion {
+		return nil, fmt.Errorf("%w: transaction Version is %d", ErrInvalidBlock, v)
 	}
+	t.Timestamp = fbTimestamp(int64(uint48(r.next(6, "MilliTimestamp"))))
+	inputs := int(r.uint8("InputCount"))
+	outputs := int(r.uint8("OutputCount"))
+	ecOutputs := int(r.uint8("ECOutputCount"))
 
+	if inputs > 0 {
+		t.Inputs = make([]SignedTransactionAddress, inputs)
+	}
+	for i := range t.Inputs {
+		a, p := readTransactionAddress(r, fmt.Sprintf("Inputs[%d]", i))
+		a.Address = FactoidAddressFromRCDHash(p)
+		t.Inputs[i].TransactionAddress = a
+	}
+	t.Outputs = make([]TransactionAddress, outputs)
+	for i := range t.Outputs {
+		a, p := readTransactionAddress(r, fmt.Sprintf("Outputs[%d]", i))
+		a.Address = FactoidAddressFromRCDHash(p)
+		t.Outputs[i] = a
+	}
+	t.ECOutputs = make([]TransactionAddress, ecOutputs)
+	for i := range t.ECOutputs {
+		a, p := readTransactionAddress(r, fmt.Sprintf("ECOutputs[%d]", i))
+		ec := &ECAddress{Pub: new([32]byte)}
+		copy(ec.Pub[:], p)
+		a.Address = ec.PubString()
+		t.ECOutputs[i] = a
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	t.TxID = hex.EncodeToString(sha(r.data[start:r.off]))
+
+	// each input is followed by its RCD and signature block, only type 1
+	// RCDs with a single signature are supported
+	for i := range t.Inputs {
+		rcd := r.next(rcd1Size, fmt.Sprintf("Inputs[%d].RCD", i))
+		if rcd != nil && rcd[0] != 1 {
+			return nil, fmt.Errorf("%w: Inputs[%d].RCD has unsupported type %d",
+				ErrInvalidBlock, i, rcd[0])
+		}
+		t.Inputs[i].RCD = hex.EncodeToString(rcd)
+		t.Inputs[i].Signatures = []string{r.hex(signatureSize, fmt.Sprintf("Inputs[%d].Signatures[0]", i))}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return t, nil
+}
+
+// readTransactionAddress reads a varint amount followed by a 32 byte RCD Hash
+// or EC public key, which is also returned as bytes.
+func readTransactionAddress(r *blockReader, field string) (TransactionAddress, []byte) {
+	var a TransactionAddress
+	a.Amount = r.varint(field + ".Amount")
+	p := r.next(32, field+".RCDHash")
+	a.RCDHash = hex.EncodeToString(p)
+	return a, p
+}
+
+// MarshalBinary encodes the transaction in the binary form used by factomd.
+func (t *FBTransaction) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := t.marshalBinarySig(buf); err != nil {
+		return nil, err
+	}
+
+	for i, in := range t.Inputs {
+		field := fmt.Sprintf("Inputs[%d].RCD", i)
+		if err := writeHex(buf, in.RCD, rcd1Size, field); err != nil {
+			return nil, err
+		}
+		if typ := buf.Bytes()[buf.Len()-rcd1Size]; typ != 1 {
+			return nil, fmt.Errorf("%s: unsupported type %d", field, typ)
+		}
+		if len(in.Signatures) != 1 {
+			return nil, fmt.Errorf("Inputs[%d].Signatures: expected 1, got %d",
+				i, len(in.Signatures))
+		}
+		if err := writeHex(buf, in.Signatures[0], signatureSize, fmt.Sprintf("Inputs[%d].Signatures[0]", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// marshalBinarySig writes the part of the transaction that is signed and
+// hashed for the TxID; everything but the RCDs and signatures.
+func (t *FBTransaction) marshalBinarySig(buf *bytes.Buffer) error {
+	if len(t.Inputs) > math.MaxUint8 || len(t.Outputs) > math.MaxUint8 || len(t.ECOutputs) > math.MaxUint8 {
+		return fmt.Errorf("a transaction can have at most %d of each of Inputs, Outputs and ECOutputs",
+			math.MaxUint8)
+	}
+
+	writeVarInt(buf, fbTransactionVersion)
+	buf.Write(putUint48(uint64(fbMilliTimestamp(t.Timestamp))))
+	buf.WriteByte(byte(len(t.Inputs)))
+	buf.WriteByte(byte(len(t.Outputs)))
+	buf.WriteByte(byte(len(t.ECOutputs)))
+
+	for i, in := range t.Inputs {
+		writeVarInt(buf, in.Amount)
+		if err := writeHash(buf, in.RCDHash, fmt.Sprintf("Inputs[%d].RCDHash", i)); err != nil {
+			return err
+		}
+	}
+	for i, out := range t.Outputs {
+		writeVarInt(buf, out.Amount)
+		if err := writeHash(buf, out.RCDHash, fmt.Sprintf("Outputs[%d].RCDHash", i)); err != nil {
+			return err
+		}
+	}
+	for i, ec := range t.ECOutputs {
+		writeVarInt(buf, ec.Amount)
+		if err := writeHash(buf, ec.RCDHash, fmt.Sprintf("ECOutputs[%d].RCDHash", i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ComputeTxID computes the Transaction ID, the hash of the transaction
+// without its RCDs and signatures.
+func (t *FBTransaction) ComputeTxID() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := t.marshalBinarySig(buf); err != nil {
+		return nil, err
+	}
+	return sha(buf.Bytes()), nil
+}
+
+// ComputeFullHash computes the hash of the whole binary transaction.
+func (t *FBTransaction) ComputeFullHash() ([]byte, error) {
+	p, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return sha(p), nil
+}
+
+// UnmarshalBinary decodes a binary Factoid Block, such as the raw data
+// returned by GetRaw. The end of minute markers of the body set the Minute of
+// each FBTransaction and the ChainID, KeyMR, LedgerKeyMR and TxIDs are
+// computed from the decoded block.
+func (f *FBlock) UnmarshalBinary(data []byte) error {
+	_, err := f.UnmarshalBinaryData(data)
+	return err
+}
+
+// UnmarshalBinaryData decodes a binary Factoid Block and returns the
+// remainder of the byte stream. An error is returned if the BodyMR in the
+// header does not match the body.
+func (f *FBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	r := newBlockReader(data)
+
+	n := new(FBlock)
+	if id := r.hash("FactoidChainID"); r.err == nil && id != factoidChainID {
+		return nil, fmt.Errorf("%w: FactoidChainID is %s", ErrInvalidBlock, id)
+	}
+	n.ChainID = factoidChainID
+	n.BodyMR = r.hash("BodyMR")
+	n.PrevKeyMR = r.hash("PrevKeyMR")
+	n.PrevLedgerKeyMR = r.hash("PrevLedgerKeyMR")
+	n.ExchRate = int64(r.uint64("ExchRate"))
+	n.DBHeight = int64(r.uint32("DBHeight"))
+	n.HeaderExpansionArea = hex.EncodeToString(r.next(int(r.varint("HeaderExpansionSize")), "HeaderExpansionArea"))
+	count := int(r.uint32("TransactionCount"))
+	size := r.uint32("BodySize")
+	if r.err != nil {
+		return nil, r.err
+	}
+	if l := uint32(len(r.remaining())); size > l {
+		return nil, fmt.Errorf("%w: BodySize is %d bytes but %d remain",
+			ErrBlockTruncated, size, l)
+	}
+	header := data[:r.off]
+
+	// the body is a list of transactions, each minute followed by a single
+	// zero byte end of minute marker
+	br := newBlockReader(r.next(int(size), "Body"))
+	minute := 1
+	for len(br.remaining()) > 0 {
+		if br.remaining()[0] == 0 {
+			br.off++
+			minute++
+			continue
+		}
+		i := len(n.Transactions)
+		if minute > 10 {
+			return nil, fmt.Errorf("%w: Transactions[%d] follows the last end of minute marker",
+				ErrInvalidBlock, i)
+		}
+		t, err := unmarshalFBTransaction(br)
+		if err != nil {
+			return nil, fmt.Errorf("Transactions[%d]: %w", i, err)
+		}
+		t.Minute = minute
+		n.Transactions = append(n.Transactions, t)
+	}
+	if minute > 11 {
+		return nil, fmt.Errorf("%w: body has %d end of minute markers",
+			ErrInvalidBlock, minute-1)
+	}
+	if len(n.Transactions) != count {
+		return nil, fmt.Errorf("%w: TransactionCount is %d but the body has %d transactions",
+			ErrInvalidBlock, count, len(n.Transactions))
+	}
+
+	bodyMR, err := n.ComputeBodyMR()
+	if err != nil {
+		return nil, err
+	}
+	if hex.EncodeToString(bodyMR) != n.BodyMR {
+		return nil, fmt.Errorf("%w: header has %s, body has %x",
+			ErrBodyMRMismatch, n.BodyMR, bodyMR)
+	}
+
+	headerHash := sha(header)
+	n.KeyMR = hex.EncodeToString(sha(append(headerHash, bodyMR...)))
+	ledgerMR, err := n.computeMerkleRoot((*FBTransaction).ComputeTxID)
+	if err != nil {
+		return nil, err
+	}
+	n.LedgerKeyMR = hex.EncodeToString(sha(append(ledgerMR, headerHash...)))
+
+	*f = *n
+	return r.remaining(), nil
+}
+
+// MarshalBinary encodes the Factoid Block in the binary form used by factomd.
+// Transactions are grouped by Minute and followed by all ten end of minute
+// markers; a transaction with no Minute stays in the minute of the one before
+// it.
+func (f *FBlock) MarshalBinary() ([]byte, error) {
+	body, err := f.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	h, err := f.marshalHeader(len(body))
+	if err != nil {
+		return nil, err
+	}
+	return append(h, body...), nil
+}
+
+func (f *FBlock) marshalHeader(bodySize int) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// 32 byte FactoidChainID, BodyMR, PrevKeyMR and PrevLedgerKeyMR
+	writeHash(buf, factoidChainID, "FactoidChainID")
+	if err := writeHash(buf, f.BodyMR, "BodyMR"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, f.PrevKeyMR, "PrevKeyMR"); err != nil {
+		return nil, err
+	}
+	if err := writeHash(buf, f.PrevLedgerKeyMR, "PrevLedgerKeyMR"); err != nil {
+		return nil, err
+	}
+
+	// 8 byte ExchRate and 4 byte DBHeight
+	binary.Write(buf, binary.BigEndian, uint64(f.ExchRate))
+	binary.Write(buf, binary.BigEndian, uint32(f.DBHeight))
+
+	// varint HeaderExpansionSize and HeaderExpansionArea
+	x, err := hex.DecodeString(f.HeaderExpansionArea)
+	if err != nil {
+		return nil, fmt.Errorf("HeaderExpansionArea: %w", err)
+	}
+	writeVarInt(buf, uint64(len(x)))
+	buf.Write(x)
+
+	// 4 byte TransactionCount and BodySize
+	binary.Write(buf, binary.BigEndian, uint32(len(f.Transactions)))
+	binary.Write(buf, binary.BigEndian, uint32(bodySize))
+
+	return buf.Bytes(), nil
+}
+
+func (f *FBlock) marshalBody() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := f.walkBody(func(t *FBTransaction) error {
+		if t == nil {
+			buf.WriteByte(0)
+			return nil
+		}
+		p, err := t.MarshalBinary()
+		buf.Write(p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// walkBody calls fn for each transaction of the body in order, and with nil
+// for each end of minute marker.
+func (f *FBlock) walkBody(fn func(*FBTransaction) error) error {
+	minute := 1
+	for i, t := range f.Transactions {
+		if t.Minute > 10 || t.Minute != 0 && t.Minute < minute {
+			return fmt.Errorf("Transactions[%d]: Minute %d cannot follow minute %d",
+				i, t.Minute, minute)
+		}
+		for ; minute < t.Minute; minute++ {
+			if err := fn(nil); err != nil {
+				return err
+			}
+		}
+		if err := fn(t); err != nil {
+			return fmt.Errorf("Transactions[%d]: %w", i, err)
+		}
+	}
+	for ; minute <= 10; minute++ {
+		if err := fn(nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// computeMerkleRoot computes the Merkle root of the hash of each transaction
+// and end of minute marker of the body.
+func (f *FBlock) computeMerkleRoot(hash func(*FBTransaction) ([]byte, error)) ([]byte, error) {
+	var hashes [][]byte
+	err := f.walkBody(func(t *FBTransaction) error {
+		if t == nil {
+			hashes = append(hashes, sha([]byte{0}))
+			return nil
+		}
+		h, err := hash(t)
+		hashes = append(hashes, h)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merkleRoot(hashes), nil
+}
+
+// ComputeBodyMR computes the Merkle root of the full hashes of the
+// transactions and end of minute markers of the Factoid Block.
+func (f *FBlock) ComputeBodyMR() ([]byte, error) {
+	return f.computeMerkleRoot((*FBTransaction).ComputeFullHash)
+}
+
+// ComputeHeaderHash computes the hash of the Factoid Block header.
+func (f *FBlock) ComputeHeaderHash() ([]byte, error) {
+	body, err := f.marshalBody()
+	if err != nil {
+		return nil, err
+	}
+	h, err := f.marshalHeader(len(body))
+	if err != nil {
+		return nil, err
+	}
+	return sha(h), nil
+}
+
+// ComputeKeyMR computes the Key Merkle Root of the Factoid Block from its
+// header and the BodyMR of its transactions.
+func (f *FBlock) ComputeKeyMR() ([]byte, error) {
+	h, err := f.ComputeHeaderHash()
+	if err != nil {
+		return nil, err
+	}
+	bodyMR, err := f.ComputeBodyMR()
+	if err != nil {
+		return nil, err
+	}
+	return sha(append(h, bodyMR...)), nil
+}
+
+// ComputeLedgerKeyMR computes the Ledger Key Merkle Root of the Factoid Block
+// from the TxIDs of its transactions and its header.
+func (f *FBlock) ComputeLedgerKeyMR() ([]byte, error) {
+	h, err := f.ComputeHeaderHash()
+	if err != nil {
+		return nil, err
+	}
+	ledgerMR, err := f.computeMerkleRoot((*FBTransaction).ComputeTxID)
+	if err != nil {
+		return nil, err
+	}
+	return sha(append(ledgerMR, h...)), nil
+}
+
+// GetFBlock requests a specified Factoid Block from factomd by its keymr
+func (c *Client) GetFBlock(ctx context.Context, keymr string) (fblock *FBlock, err error) {
+	params := keyMRRequest{KeyMR: keymr, NoRaw: true}
+	req := NewJSON2Request("factoid-block", APICounter(), params)
 	// Create temporary struct to unmarshal json object
 	wrap := new(struct {
 		FBlock *FBlock `json:"fblock"`
 	})
 
-	if err = json.Unmarshal(resp.JSONResult(), wrap); err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
@@ -199,21 +641,13 @@ func GetFBlock(keymr string) (fblock *FBlock, err error) {
 }
 
 // GetFBlockByHeight requests a specified Factoid Block from factomd by its height
-func GetFBlockByHeight(height int64) (fblock *FBlock, err error) {
+func (c *Client) GetFBlockByHeight(ctx context.Context, height int64) (fblock *FBlock, err error) {
 	params := heightRequest{Height: height, NoRaw: true}
 	req := NewJSON2Request("fblock-by-height", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	wrap := new(struct {
 		FBlock *FBlock `json:"fblock"`
 	})
-	if err = json.Unmarshal(resp.JSONResult(), wrap); err != nil {
+	if err = c.factomdCall(ctx, req, wrap); err != nil {
 		return
 	}
 
@@ -6,11 +6,15 @@ package factom_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	. "github.com/FactomProject/factom"
 )
@@ -31,6 +35,160 @@ func TestUnmarshalFBlock(t *testing.T) {
 	t.Log(wrap.FBlock)
 }
 
+func TestFBlockUnmarshalBinary(t *testing.T) {
+	js := []byte(`{"fblock":{"bodymr":"0b6823522198d47689065e7b492baafbf817f0036934afffd1c968f2533a3e84","prevkeymr":"48c432b586b1737bc8ea0349ec319e41f07b28bc89d94b2e970e09f494eb8e04","prevledgerkeymr":"7a7c9851d9bcfb00f4d3d4cd0179adb43e47aabed628e7fceaf0ca718853045b","exchrate":90900,"dbheight":20002,"transactions":[{"txid":"fab98df81a80b1177c5226ff307be7ecc77c30666c63f06623a606424d41fe72","blockheight":0,"millitimestamp":1453149000985,"inputs":[],"outputs":[],"outecs":[],"rcds":[],"sigblocks":[]},{"txid":"1ec91421e01d95267f3deb9b9d5f29d3438387a0280a5ffa5e9a60f235212ae8","blockheight":0,"millitimestamp":1453149058599,"inputs":[{"amount":26268275436,"address":"3d956f129c08ac413025be3f6e47e3fb26461df35c9ccaf2fe4d53373e52536b","useraddress":"FA2SCdYb8iBYmMcmeUjHB8NhKx6DqH3wDovkumgbKt4oNkD3TJMg"}],"outputs":[{"amount":26267184636,"address":"ccf82cf94557f08a6859d8bf4a9b3ce361d0abae1e3bf5136b24638b74d32bc6","useraddress":"FA3XME5vdcjG8jPT188UFkum9BeAJJLgwyCkGB12QLsDA2qQaBET"}],"outecs":[],"rcds":["016664074524dd6a58e6593780717233b56d381a6798e5ee5ba75564bde589a6bf"],"sigblocks":[{"signatures":["efdab088b50d56ea2dfd4f600d5727a06cd7e9f3c353288e6898723ea32f4f044d27a80a199cfefec06cf53e18ea863b05b1075001d592b913e7f32c3d3f2204"]}]}],"chainid":"000000000000000000000000000000000000000000000000000000000000000f","keymr":"cfcac07b29ccfa413aeda646b5d386006468189939dfdfa6415b97cc35f2ea1a","ledgerkeymr":"a47da86f6ac8111da8a7d2a64fbaed1f74839722276acc5773b908963d01a029"},"rawdata":"000000000000000000000000000000000000000000000000000000000000000f0b6823522198d47689065e7b492baafbf817f0036934afffd1c968f2533a3e8448c432b586b1737bc8ea0349ec319e41f07b28bc89d94b2e970e09f494eb8e047a7c9851d9bcfb00f4d3d4cd0179adb43e47aabed628e7fceaf0ca718853045b000000000001631400004e220000000002000000c9020152566e1519000000020152566ef627010100e1edd8a56c3d956f129c08ac413025be3f6e47e3fb26461df35c9ccaf2fe4d53373e52536be1ed95db7cccf82cf94557f08a6859d8bf4a9b3ce361d0abae1e3bf5136b24638b74d32bc6016664074524dd6a58e6593780717233b56d381a6798e5ee5ba75564bde589a6bfefdab088b50d56ea2dfd4f600d5727a06cd7e9f3c353288e6898723ea32f4f044d27a80a199cfefec06cf53e18ea863b05b1075001d592b913e7f32c3d3f220400000000000000000000"}`)
+
+	wrap := new(struct {
+		FBlock  *FBlock `json:"fblock"`
+		RawData string  `json:"rawdata"`
+	})
+	if err := json.Unmarshal(js, wrap); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := hex.DecodeString(wrap.RawData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fb := new(FBlock)
+	if err := fb.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	// the minute of each transaction is only known from the binary block
+	for _, tx := range wrap.FBlock.Transactions {
+		tx.Minute = 1
+	}
+	if !reflect.DeepEqual(fb, wrap.FBlock) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", wrap.FBlock, fb)
+	}
+
+	p, err := fb.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p, raw) {
+		t.Errorf("MarshalBinary does not match the raw data:\n%x\n%x", p, raw)
+	}
+	if h, _ := fb.ComputeBodyMR(); hex.EncodeToString(h) != wrap.FBlock.BodyMR {
+		t.Errorf("unexpected BodyMR %x", h)
+	}
+	if h, _ := fb.ComputeKeyMR(); hex.EncodeToString(h) != wrap.FBlock.KeyMR {
+		t.Errorf("unexpected KeyMR %x", h)
+	}
+	if h, _ := fb.ComputeLedgerKeyMR(); hex.EncodeToString(h) != wrap.FBlock.LedgerKeyMR {
+		t.Errorf("unexpected LedgerKeyMR %x", h)
+	}
+	for i, tx := range fb.Transactions {
+		if h, _ := tx.ComputeTxID(); hex.EncodeToString(h) != tx.TxID {
+			t.Errorf("Transactions[%d]: unexpected TxID %x", i, h)
+		}
+	}
+
+	if err := fb.UnmarshalBinary(raw[:len(raw)-20]); !errors.Is(err, ErrBlockTruncated) {
+		t.Errorf("expected %v, got %v", ErrBlockTruncated, err)
+	}
+
+	// flip a byte of the signature of the second transaction
+	tampered := append([]byte{}, raw...)
+	tampered[len(tampered)-11] ^= 0xff
+	if err := fb.UnmarshalBinary(tampered); !errors.Is(err, ErrBodyMRMismatch) {
+		t.Errorf("expected %v, got %v", ErrBodyMRMismatch, err)
+	}
+}
+
+func TestFBlockBinaryRoundTrip(t *testing.T) {
+	hash := func(b byte) string {
+		return hex.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	}
+
+	fb := &FBlock{
+		PrevKeyMR:           hash(0x01),
+		PrevLedgerKeyMR:     hash(0x02),
+		ExchRate:            2000,
+		DBHeight:            200000,
+		HeaderExpansionArea: "0102",
+		Transactions: []*FBTransaction{
+			{
+				Timestamp: time.Unix(1600000000, 123000),
+				Outputs:   []TransactionAddress{{Amount: 666600000, RCDHash: hash(0x03)}},
+				ECOutputs: []TransactionAddress{},
+				Minute:    1,
+			},
+			{
+				Timestamp: time.Unix(1600000300, 0),
+				Inputs: []SignedTransactionAddress{{
+					TransactionAddress: TransactionAddress{Amount: 1000012000, RCDHash: hash(0x04)},
+					RCD:                "01" + hash(0x05),
+					Signatures:         []string{hash(0x06) + hash(0x07)},
+				}},
+				Outputs:   []TransactionAddress{{Amount: 1000000000, RCDHash: hash(0x08)}},
+				ECOutputs: []TransactionAddress{{Amount: 12000, RCDHash: hash(0x09)}},
+				Minute:    4,
+			},
+		},
+	}
+	h, err := fb.ComputeBodyMR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb.BodyMR = hex.EncodeToString(h)
+
+	p, err := fb.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(FBlock)
+	if err := decoded.UnmarshalBinary(p); err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := fb.ComputeKeyMR(); decoded.KeyMR != hex.EncodeToString(h) {
+		t.Errorf("unexpected KeyMR %s", decoded.KeyMR)
+	}
+	if h, _ := fb.ComputeLedgerKeyMR(); decoded.LedgerKeyMR != hex.EncodeToString(h) {
+		t.Errorf("unexpected LedgerKeyMR %s", decoded.LedgerKeyMR)
+	}
+	if h, _ := decoded.ComputeBodyMR(); hex.EncodeToString(h) != fb.BodyMR {
+		t.Errorf("unexpected BodyMR %x", h)
+	}
+
+	for _, tx := range fb.Transactions {
+		id, _ := tx.ComputeTxID()
+		tx.TxID = hex.EncodeToString(id)
+		for i := range tx.Inputs {
+			rcdHash, _ := hex.DecodeString(tx.Inputs[i].RCDHash)
+			tx.Inputs[i].Address = FactoidAddressFromRCDHash(rcdHash)
+		}
+		for i := range tx.Outputs {
+			rcdHash, _ := hex.DecodeString(tx.Outputs[i].RCDHash)
+			tx.Outputs[i].Address = FactoidAddressFromRCDHash(rcdHash)
+		}
+	}
+	ec := &ECAddress{Pub: new([32]byte)}
+	copy(ec.Pub[:], bytes.Repeat([]byte{0x09}, 32))
+	fb.Transactions[1].ECOutputs[0].Address = ec.PubString()
+	decoded.ChainID, decoded.KeyMR, decoded.LedgerKeyMR = "", "", ""
+	if !reflect.DeepEqual(decoded, fb) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", fb, decoded)
+	}
+
+	p, err = fb.Transactions[1].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := new(FBTransaction)
+	rest, err := tx.UnmarshalBinaryData(append(p, 0x00))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, []byte{0x00}) {
+		t.Errorf("unexpected remainder %x", rest)
+	}
+	tx.Minute = fb.Transactions[1].Minute
+	if !reflect.DeepEqual(tx, fb.Transactions[1]) {
+		t.Errorf("expected:\n%s\nrecieved:\n%s", fb.Transactions[1], tx)
+	}
+}
+
 func TestGetFBlock(t *testing.T) {
 	factomdResponse := `{
 		"jsonrpc": "2.0",
@@ -5,7 +5,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -31,18 +31,10 @@ func (d *HeightsResponse) String() string {
 }
 
 // GetHeights requests the list of heights from the factomd API.
-func GetHeights() (*HeightsResponse, error) {
+func (c *Client) GetHeights(ctx context.Context) (*HeightsResponse, error) {
 	req := NewJSON2Request("heights", APICounter(), nil)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	heights := new(HeightsResponse)
-	if err := json.Unmarshal(resp.JSONResult(), heights); err != nil {
+	if err := c.factomdCall(ctx, req, heights); err != nil {
 		return nil, err
 	}
 
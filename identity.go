@@ -2,6 +2,7 @@ package factom
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -59,22 +60,25 @@ func NewIdentityChain(name []string, keys []string) (*Chain, error) {
 
 // GetActiveIdentityKeys returns the identity's public keys that were/are active at the highest saved block height,
 // along with that blockheight
-func GetActiveIdentityKeys(chainID string) ([]string, int64, error) {
-	heights, err := GetHeights()
+func (c *Client) GetActiveIdentityKeys(ctx context.Context, chainID string) ([]string, int64, error) {
+	heights, err := c.GetHeights(ctx)
 	if err != nil {
 		return nil, -1, err
 	}
-	keys, err := GetActiveIdentityKeysAtHeight(chainID, heights.DirectoryBlockHeight)
+	keys, err := c.GetActiveIdentityKeysAtHeight(ctx, chainID, heights.DirectoryBlockHeight)
 	return keys, heights.DirectoryBlockHeight, err
 }
 
 // GetActiveIdentityKeysAtHeight returns the identity's public keys that were active at the specified block height
-func GetActiveIdentityKeysAtHeight(chainID string, height int64) ([]string, error) {
-	if !ChainExists(chainID) {
+func (c *Client) GetActiveIdentityKeysAtHeight(ctx context.Context, chainID string, height int64) ([]string, error) {
+	if !c.ChainExists(ctx, chainID) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("chain does not exist")
 	}
 
-	entries, err := GetAllChainEntriesAtHeight(chainID, height)
+	entries, err := c.GetAllChainEntriesAtHeight(ctx, chainID, height)
 	if err != nil {
 		return nil, err
 	} else if len(entries) == 0 {
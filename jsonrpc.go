@@ -5,13 +5,9 @@
 package factom
 
 import (
-	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"net/http/cookiejar"
 	"strings"
 	"sync/atomic"
@@ -25,6 +21,7 @@ type RPCConfig struct {
 	WalletTLSEnable    bool
 	WalletTLSKeyFile   string
 	WalletTLSCertFile  string
+	WalletTLS          *TLSConfig
 	WalletRPCUser      string
 	WalletRPCPassword  string
 	WalletServer       string
@@ -32,10 +29,18 @@ type RPCConfig struct {
 	WalletCORSDomains  string
 	FactomdTLSEnable   bool
 	FactomdTLSCertFile string
+	FactomdTLS         *TLSConfig
 	FactomdRPCUser     string
 	FactomdRPCPassword string
 	FactomdServer      string
 	FactomdTimeout     time.Duration
+	FactomdRetry       *RetryPolicy
+	FactomdCache       Cache
+	FactomdLimiter     *RateLimiter
+	WalletLimiter      *RateLimiter
+
+	FactomdMaxResponseSize int64
+	WalletMaxResponseSize  int64
 }
 
 func EncodeJSON(data interface{}) ([]byte, error) {
@@ -54,6 +59,45 @@ func EncodeJSONString(data interface{}) (string, error) {
 	return string(encoded), err
 }
 
+// Errors returned by the factomd and factom-walletd APIs. A *JSONError from
+// an API call matches these with errors.Is, so callers do not need to compare
+// the error messages; the *JSONError itself remains available with errors.As.
+var (
+	ErrParse            = errors.New("Parse error")
+	ErrInvalidRequest   = errors.New("Invalid Request")
+	ErrMethodNotFound   = errors.New("Method not found")
+	ErrInvalidParams    = errors.New("Invalid params")
+	ErrInternal         = errors.New("Internal error")
+	ErrBlockNotFound    = errors.New("Block not found")
+	ErrEntryNotFound    = errors.New("Entry not found")
+	ErrObjectNotFound   = errors.New("Object not found")
+	ErrMissingChainHead = errors.New("Missing Chain Head")
+	ErrReceiptCreation  = errors.New("Receipt creation error")
+	ErrRepeatedCommit   = errors.New("Repeated Commit")
+	ErrWalletLocked     = errors.New("Wallet is locked")
+)
+
+// jsonErrorCodes maps the standard JSON RPC error codes to their errors.
+var jsonErrorCodes = map[int]error{
+	-32700: ErrParse,
+	-32600: ErrInvalidRequest,
+	-32601: ErrMethodNotFound,
+	-32602: ErrInvalidParams,
+	-32603: ErrInternal,
+}
+
+// jsonErrorMessages are the API errors that are told apart by their message
+// rather than their code, since the servers share codes between them.
+var jsonErrorMessages = []error{
+	ErrBlockNotFound,
+	ErrEntryNotFound,
+	ErrObjectNotFound,
+	ErrMissingChainHead,
+	ErrReceiptCreation,
+	ErrRepeatedCommit,
+	ErrWalletLocked,
+}
+
 type JSONError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
@@ -76,6 +120,27 @@ func (e *JSONError) Error() string {
 	return s
 }
 
+// Is reports whether the JSONError is the API error target, so that errors.Is
+// can be used with the errors listed above. The message of the error may be
+// given in either the Message or the Data of the JSONError.
+func (e *JSONError) Is(target error) bool {
+	if err, ok := jsonErrorCodes[e.Code]; ok && err == target {
+		return true
+	}
+	for _, err := range jsonErrorMessages {
+		if err != target {
+			continue
+		}
+		if strings.EqualFold(e.Message, err.Error()) {
+			return true
+		}
+		if data, ok := e.Data.(string); ok && strings.Contains(strings.ToLower(data), strings.ToLower(err.Error())) {
+			return true
+		}
+	}
+	return false
+}
+
 type JSON2Request struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      interface{}     `json:"id"`
@@ -141,237 +206,215 @@ func (j *JSON2Response) String() string {
 	return str
 }
 
+// SetRpcConfig replaces the package level RpcConfig, such as with an
+// RPCConfig from LoadConfig.
+func SetRpcConfig(cfg *RPCConfig) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig = cfg
+}
+
 func SetFactomdRpcConfig(user string, password string) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.FactomdRPCUser = user
 	RpcConfig.FactomdRPCPassword = password
 }
 
 func GetFactomdRpcConfig() (string, string) {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.FactomdRPCUser, RpcConfig.FactomdRPCPassword
 }
 
 func SetFactomdEncryption(tls bool, certFile string) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.FactomdTLSEnable = tls
 	RpcConfig.FactomdTLSCertFile = certFile
 }
 
 func GetFactomdEncryption() (bool, string) {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.FactomdTLSEnable, RpcConfig.FactomdTLSCertFile
 }
 
+// SetFactomdTLSConfig sets the TLS settings used to connect to factomd. A nil
+// TLSConfig leaves only the settings of SetFactomdEncryption.
+func SetFactomdTLSConfig(t *TLSConfig) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.FactomdTLS = t
+}
+
+func GetFactomdTLSConfig() *TLSConfig {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
+	return RpcConfig.FactomdTLS
+}
+
 func SetFactomdTimeout(timeout time.Duration) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.FactomdTimeout = timeout
 }
 
 func GetFactomdTimeout() time.Duration {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.FactomdTimeout
 }
 
+func SetFactomdRetryPolicy(p *RetryPolicy) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.FactomdRetry = p
+}
+
+func GetFactomdRetryPolicy() *RetryPolicy {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
+	return RpcConfig.FactomdRetry
+}
+
+func SetFactomdCache(c Cache) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.FactomdCache = c
+}
+
+func GetFactomdCache() Cache {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
+	return RpcConfig.FactomdCache
+}
+
+func SetFactomdRateLimiter(l *RateLimiter) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.FactomdLimiter = l
+}
+
+func SetWalletRateLimiter(l *RateLimiter) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.WalletLimiter = l
+}
+
+// SetFactomdMaxResponseSize limits the size of the response bodies read from
+// factomd. A size of 0 uses DefaultMaxResponseSize and a negative size does
+// not limit them.
+func SetFactomdMaxResponseSize(size int64) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.FactomdMaxResponseSize = size
+}
+
+// SetWalletMaxResponseSize limits the size of the response bodies read from
+// factom-walletd. A size of 0 uses DefaultMaxResponseSize and a negative size
+// does not limit them.
+func SetWalletMaxResponseSize(size int64) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.WalletMaxResponseSize = size
+}
+
 func SetWalletTimeout(timeout time.Duration) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.WalletTimeout = timeout
 }
 
 func GetWalletTimeout() time.Duration {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.WalletTimeout
 }
 
 func SetWalletRpcConfig(user string, password string) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.WalletRPCUser = user
 	RpcConfig.WalletRPCPassword = password
 }
 
 func GetWalletRpcConfig() (string, string) {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.WalletRPCUser, RpcConfig.WalletRPCPassword
 }
 
 func SetWalletEncryption(tls bool, certFile string) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.WalletTLSEnable = tls
 	RpcConfig.WalletTLSCertFile = certFile
 }
 
 func GetWalletEncryption() (bool, string) {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.WalletTLSEnable, RpcConfig.WalletTLSCertFile
 }
 
+// SetWalletTLSConfig sets the TLS settings used to connect to
+// factom-walletd. A nil TLSConfig leaves only the settings of
+// SetWalletEncryption.
+func SetWalletTLSConfig(t *TLSConfig) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
+	RpcConfig.WalletTLS = t
+}
+
+func GetWalletTLSConfig() *TLSConfig {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
+	return RpcConfig.WalletTLS
+}
+
 // SetOpenNode points the Factomd server to the open node API and enables cookies
 func SetOpenNode() {
 	EnableCookies()
-	RpcConfig.FactomdServer = OpenNode
+	SetFactomdServer(OpenNode)
 }
 
 // EnableCookies will accept and manage cookies from the API server
 func EnableCookies() {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	// cookiejar.New never returns an error
 	cookieJar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 }
 
 // SetFactomdServer sets where to find the factomd server, and tells the server its public ip
 func SetFactomdServer(s string) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.FactomdServer = s
 }
 
 // SetWalletServer sets where to find the fctwallet server, and tells the server its public ip
 func SetWalletServer(s string) {
+	rpcConfigLock.Lock()
+	defer rpcConfigLock.Unlock()
 	RpcConfig.WalletServer = s
 }
 
 // FactomdServer returns where to find the factomd server, and tells the server its public ip
 func FactomdServer() string {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.FactomdServer
 }
 
 // FactomdServer returns where to find the fctwallet server, and tells the server its public ip
 func WalletServer() string {
+	rpcConfigLock.RLock()
+	defer rpcConfigLock.RUnlock()
 	return RpcConfig.WalletServer
 }
 
-// SendFactomdRequest sends a json object to factomd
-func SendFactomdRequest(req *JSON2Request) (*JSON2Response, error) {
-	return factomdRequest(req)
-}
-
-// factomdRequest sends a JSON RPC request to the factomd API server and returns
-// the corresponding API response.
-func factomdRequest(req *JSON2Request) (*JSON2Response, error) {
-	j, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-
-	factomdTls, factomdCertPath := GetFactomdEncryption()
-
-	var client *http.Client
-	var scheme, host string
-
-	if factomdTls == true {
-		caCert, err := ioutil.ReadFile(factomdCertPath)
-		if err != nil {
-			return nil, err
-		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		tr := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: caCertPool}}
-		client = &http.Client{Transport: tr, Timeout: GetFactomdTimeout()}
-		scheme = "https"
-		host = RpcConfig.FactomdServer
-
-	} else {
-		client = &http.Client{Timeout: GetFactomdTimeout()}
-		if index := strings.Index(RpcConfig.FactomdServer, "://"); index != -1 {
-			scheme = RpcConfig.FactomdServer[0:index]
-			host = RpcConfig.FactomdServer[index+3:]
-		} else {
-			scheme = "http"
-			host = RpcConfig.FactomdServer
-		}
-	}
-
-	// no effect if nil
-	client.Jar = cookieJar
-
-	re, err := http.NewRequest(
-		"POST",
-		fmt.Sprintf("%s://%s/v2", scheme, host),
-		bytes.NewBuffer(j),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	user, pass := GetFactomdRpcConfig()
-	re.SetBasicAuth(user, pass)
-	re.Header.Add("Content-Type", "application/json")
-	resp, err := client.Do(re)
-	if err != nil {
-		errs := fmt.Sprintf("%s", err)
-		if strings.Contains(errs, "\\x15\\x03\\x01\\x00\\x02\\x02\\x16") {
-			err = fmt.Errorf("Factomd API connection is encrypted. Please specify -factomdtls=true and -factomdcert=factomdAPIpub.cert (%v)", err.Error())
-		}
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("Factomd username/password incorrect.  Edit factomd.conf or\ncall factom-cli with -factomduser=<user> -factomdpassword=<pass>")
-	}
-	r := NewJSON2Response()
-	if err := json.Unmarshal(body, r); err != nil {
-		return nil, err
-	}
-
-	return r, nil
-}
-
-// walletRequest sends a JSON RPC request to the factom wallet API server and
-// returns the corresponding API response.
-func walletRequest(req *JSON2Request) (*JSON2Response, error) {
-	j, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
-
-	walletTls, walletCertPath := GetWalletEncryption()
-
-	var client *http.Client
-	var httpx string
-
-	if walletTls == true {
-		caCert, err := ioutil.ReadFile(walletCertPath)
-		if err != nil {
-			return nil, err
-		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		tr := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: caCertPool}}
-
-		client = &http.Client{Transport: tr, Timeout: GetWalletTimeout()}
-		httpx = "https"
-	} else {
-		client = &http.Client{Timeout: GetWalletTimeout()}
-		httpx = "http"
-	}
-
-	re, err := http.NewRequest(
-		"POST",
-		fmt.Sprintf("%s://%s/v2", httpx, RpcConfig.WalletServer),
-		bytes.NewBuffer(j),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	user, pass := GetWalletRpcConfig()
-	re.SetBasicAuth(user, pass)
-	re.Header.Add("Content-Type", "application/json")
-	resp, err := client.Do(re)
-	if err != nil {
-		errs := fmt.Sprintf("%s", err)
-		if strings.Contains(errs, "\\x15\\x03\\x01\\x00\\x02\\x02\\x16") {
-			err = fmt.Errorf("Factom-walletd API connection is encrypted. Please specify -wallettls=true and -walletcert=walletAPIpub.cert (%v)", err.Error())
-		}
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("Wallet username/password incorrect.  Edit factomd.conf or\ncall factom-cli with -walletuser=<user> -walletpassword=<pass>")
-	}
-	r := NewJSON2Response()
-	if err := json.Unmarshal(body, r); err != nil {
-		return nil, err
-	}
-
-	return r, nil
-}
-
 // newCounter is used to generate the ID field for the JSON2Request
 func newCounter() func() uint32 {
 	var count uint32
@@ -7,7 +7,10 @@ package factom_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 
 	. "github.com/FactomProject/factom"
 
@@ -85,3 +88,52 @@ func TestJSON2Response(t *testing.T) {
 		t.Error(e)
 	}
 }
+
+func TestJSONErrorIs(t *testing.T) {
+	for _, test := range []struct {
+		err    *JSONError
+		target error
+	}{
+		{NewJSONError(-32008, "Entry not found", nil), ErrEntryNotFound},
+		{NewJSONError(-32009, "Missing Chain Head", nil), ErrMissingChainHead},
+		{NewJSONError(-32010, "Receipt creation error", nil), ErrReceiptCreation},
+		{NewJSONError(-32602, "Invalid params", "Invalid Hash"), ErrInvalidParams},
+		{NewJSONError(-32603, "Internal error", "Wallet is locked"), ErrWalletLocked},
+		{NewJSONError(-32603, "Internal error", "Wallet is locked"), ErrInternal},
+	} {
+		if !errors.Is(test.err, test.target) {
+			t.Errorf("%v is not %v", test.err, test.target)
+		}
+	}
+
+	if errors.Is(NewJSONError(-32008, "Block not found", nil), ErrEntryNotFound) {
+		t.Error("Block not found matched ErrEntryNotFound")
+	}
+}
+
+func TestJSONErrorFromAPI(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"error":{"code":-32008,"message":"Entry not found"}}`)
+	}))
+	defer ts.Close()
+
+	SetFactomdServer(ts.URL[7:])
+
+	_, err := GetEntry("0000000000000000000000000000000000000000000000000000000000000000")
+	if !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("expected ErrEntryNotFound, got %v", err)
+	}
+	var jerr *JSONError
+	if !errors.As(err, &jerr) || jerr.Code != -32008 {
+		t.Errorf("expected a JSONError with code -32008, got %v", err)
+	}
+
+	// the pending entries used to drop the API error
+	if _, err := GetPendingEntries(); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("expected ErrEntryNotFound from GetPendingEntries, got %v", err)
+	}
+	if _, _, err := FactoidSubmit("00"); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("expected ErrEntryNotFound from FactoidSubmit, got %v", err)
+	}
+}
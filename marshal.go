@@ -0,0 +1,217 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrBlockTruncated   = errors.New("block data truncated")
+	ErrInvalidBlock     = errors.New("invalid block data")
+	ErrBodyMRMismatch   = errors.New("BodyMR does not match the block body")
+	ErrBodyHashMismatch = errors.New("BodyHash does not match the block body")
+)
+
+// blockReader reads the fields of a binary block in order. After the first
+// error every read returns the zero value and the error is kept in err.
+type blockReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func newBlockReader(data []byte) *blockReader {
+	return &blockReader{data: data}
+}
+
+// next returns the next n bytes of the block.
+func (r *blockReader) next(n int, field string) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data)-r.off < n {
+		r.err = fmt.Errorf("%w: %s needs %d bytes at offset %d but %d remain",
+			ErrBlockTruncated, field, n, r.off, len(r.data)-r.off)
+		return nil
+	}
+	p := r.data[r.off : r.off+n]
+	r.off += n
+	return p
+}
+
+// bytes returns a copy of the next n bytes of the block, or nil if n is 0.
+func (r *blockReader) bytes(n int, field string) []byte {
+	p := r.next(n, field)
+	if len(p) == 0 {
+		return nil
+	}
+	return append([]byte{}, p...)
+}
+
+// hash returns the next 32 bytes of the block as hex.
+func (r *blockReader) hash(field string) string {
+	return r.hex(32, field)
+}
+
+// hex returns the next n bytes of the block as hex.
+func (r *blockReader) hex(n int, field string) string {
+	return hex.EncodeToString(r.next(n, field))
+}
+
+func (r *blockReader) uint8(field string) uint8 {
+	p := r.next(1, field)
+	if p == nil {
+		return 0
+	}
+	return p[0]
+}
+
+func (r *blockReader) uint16(field string) uint16 {
+	p := r.next(2, field)
+	if p == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(p)
+}
+
+func (r *blockReader) uint32(field string) uint32 {
+	p := r.next(4, field)
+	if p == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(p)
+}
+
+func (r *blockReader) uint64(field string) uint64 {
+	p := r.next(8, field)
+	if p == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(p)
+}
+
+// varint reads a Factom variable length integer; big endian groups of 7 bits
+// with the high bit set on every byte but the last.
+func (r *blockReader) varint(field string) uint64 {
+	var v uint64
+	for i := 0; ; i++ {
+		p := r.next(1, field)
+		if p == nil {
+			return 0
+		}
+		if i == 10 || v>>57 != 0 {
+			r.err = fmt.Errorf("%w: %s varint at offset %d overflows 64 bits",
+				ErrInvalidBlock, field, r.off-i-1)
+			return 0
+		}
+		v = v<<7 | uint64(p[0]&0x7f)
+		if p[0]&0x80 == 0 {
+			return v
+		}
+	}
+}
+
+// uint48 decodes a 6 byte big endian integer, such as a millisecond timestamp.
+func uint48(p []byte) uint64 {
+	if len(p) < 6 {
+		return 0
+	}
+	return uint64(p[0])<<40 | uint64(p[1])<<32 | uint64(binary.BigEndian.Uint32(p[2:]))
+}
+
+// putUint48 encodes a 6 byte big endian integer.
+func putUint48(v uint64) []byte {
+	p := make([]byte, 8)
+	binary.BigEndian.PutUint64(p, v)
+	return p[2:]
+}
+
+// remaining returns the rest of the block data.
+func (r *blockReader) remaining() []byte {
+	return r.data[r.off:]
+}
+
+// writeHash writes a 32 byte hex hash to buf.
+func writeHash(buf *bytes.Buffer, h string, field string) error {
+	return writeHex(buf, h, 32, field)
+}
+
+// writeHex writes n bytes of hex to buf.
+func writeHex(buf *bytes.Buffer, h string, n int, field string) error {
+	p, err := hex.DecodeString(h)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	if len(p) != n {
+		return fmt.Errorf("%s: expected %d bytes, got %d", field, n, len(p))
+	}
+	buf.Write(p)
+	return nil
+}
+
+// writeVarInt writes a Factom variable length integer to buf.
+func writeVarInt(buf *bytes.Buffer, v uint64) {
+	var p [10]byte
+	i := len(p) - 1
+	p[i] = byte(v & 0x7f)
+	for v >>= 7; v != 0; v >>= 7 {
+		i--
+		p[i] = byte(v&0x7f) | 0x80
+	}
+	buf.Write(p[i:])
+}
+
+// merkleRoot computes the Merkle root of a list of hashes the way factomd
+// does; an odd node at the end of a level is paired with itself.
+func merkleRoot(hashes [][]byte) []byte {
+	if len(hashes) == 0 {
+		return make([]byte, 32)
+	}
+
+	level := hashes
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, sha(append(append([]byte{}, level[i]...), right...)))
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// merkleStep checks that node is the left or right node of a step of a Merkle
+// branch and that the two hash to the top node, which is returned.
+func merkleStep(node []byte, left, right, top string) ([]byte, error) {
+	l, err := hex.DecodeString(left)
+	if err != nil || len(l) != 32 {
+		return nil, fmt.Errorf("Left %q is not a 32 byte hash", left)
+	}
+	r, err := hex.DecodeString(right)
+	if err != nil || len(r) != 32 {
+		return nil, fmt.Errorf("Right %q is not a 32 byte hash", right)
+	}
+	t, err := hex.DecodeString(top)
+	if err != nil || len(t) != 32 {
+		return nil, fmt.Errorf("Top %q is not a 32 byte hash", top)
+	}
+
+	if !bytes.Equal(node, l) && !bytes.Equal(node, r) {
+		return nil, fmt.Errorf("does not include %x", node)
+	}
+	if h := sha(append(l, r...)); !bytes.Equal(h, t) {
+		return nil, fmt.Errorf("Top is %x but Left and Right hash to %x", t, h)
+	}
+	return t, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Handler sends a JSON RPC request to an API server and returns the response.
+// The header holds the HTTP headers sent with the request.
+type Handler func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error)
+
+// Middleware wraps a Handler to act on every request sent by a Client or
+// WalletClient. A Middleware may inspect or change the request and its HTTP
+// headers before calling next, and inspect the response, the error and the
+// duration of the call after.
+//
+// If the headers include an Authorization header, the RPCUser and RPCPassword
+// of the client are not sent. The requests of a batch each pass through the
+// Middleware and are sent together, so a Middleware must call next at most
+// once per request.
+type Middleware func(next Handler) Handler
+
+// chainMiddleware wraps the Handler h in the Middleware, the first Middleware
+// being the outermost.
+func chainMiddleware(mws []Middleware, h Handler) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// setHeader sets the headers of an API request. The basic auth credentials
+// are only used if no Authorization header was set by a Middleware.
+func setHeader(re *http.Request, header http.Header, user, password string) {
+	for k, vs := range header {
+		for _, v := range vs {
+			re.Header.Add(k, v)
+		}
+	}
+	if re.Header.Get("Authorization") == "" {
+		re.SetBasicAuth(user, password)
+	}
+	re.Header.Set("Content-Type", "application/json")
+}
+
+// batchMiddleware passes every request of a batch through the Middleware.
+// Once each request has either reached the innermost Handler or been answered
+// by a Middleware, the requests that reached the Handler are sent as a single
+// batch with the union of their HTTP headers.
+func batchMiddleware(
+	ctx context.Context,
+	mws []Middleware,
+	reqs []*JSON2Request,
+	send func(context.Context, []*JSON2Request, http.Header) ([]*JSON2Response, error),
+) ([]*JSON2Response, error) {
+	type result struct {
+		resp *JSON2Response
+		err  error
+	}
+	type arrival struct {
+		i      int
+		req    *JSON2Request
+		header http.Header
+		result chan result
+	}
+
+	arrivals := make(chan *arrival)
+	finished := make(chan int)
+	resps := make([]*JSON2Response, len(reqs))
+	errs := make([]error, len(reqs))
+
+	for i, req := range reqs {
+		go func(i int, req *JSON2Request) {
+			h := chainMiddleware(mws, func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+				a := &arrival{i: i, req: req, header: header, result: make(chan result, 1)}
+				arrivals <- a
+				r := <-a.result
+				return r.resp, r.err
+			})
+			resps[i], errs[i] = h(ctx, req, make(http.Header))
+			finished <- i
+		}(i, req)
+	}
+
+	// wait for every request to reach the Handler or to be answered early
+	arrived := make([]*arrival, 0, len(reqs))
+	isArrived := make(map[int]bool)
+	done := make(map[int]bool)
+	for len(arrived)+len(done) < len(reqs) {
+		select {
+		case a := <-arrivals:
+			arrived = append(arrived, a)
+			isArrived[a.i] = true
+		case i := <-finished:
+			done[i] = true
+		}
+	}
+
+	if len(arrived) > 0 {
+		sort.Slice(arrived, func(i, j int) bool { return arrived[i].i < arrived[j].i })
+		batch := make([]*JSON2Request, len(arrived))
+		header := make(http.Header)
+		for i, a := range arrived {
+			batch[i] = a.req
+			for k, vs := range a.header {
+				if _, ok := header[k]; !ok {
+					header[k] = vs
+				}
+			}
+		}
+
+		rs, err := send(ctx, batch, header)
+		for i, a := range arrived {
+			if err != nil {
+				a.result <- result{err: err}
+			} else {
+				a.result <- result{resp: rs[i]}
+			}
+		}
+	}
+
+	for i := range reqs {
+		if isArrived[i] && !done[i] {
+			<-finished
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resps, nil
+}
+
+// HeaderMiddleware returns a Middleware that sets an HTTP header on every
+// request, such as the API key required by a hosted API provider.
+func HeaderMiddleware(key, value string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+			header.Set(key, value)
+			return next(ctx, req, header)
+		}
+	}
+}
+
+// LoggingMiddleware returns a Middleware that logs the method, id, duration
+// and error of every request as key=value pairs. If logger is nil the
+// standard logger is used.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.New(log.Writer(), "", log.LstdFlags)
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req, header)
+			d := time.Since(start)
+
+			s := fmt.Sprintf("method=%s id=%v duration=%s", req.Method, req.ID, d)
+			if err != nil {
+				s += fmt.Sprintf(" error=%q", err.Error())
+			} else if resp.Error != nil {
+				s += fmt.Sprintf(" code=%d error=%q", resp.Error.Code, resp.Error.Error())
+			}
+			logger.Println(s)
+
+			return resp, err
+		}
+	}
+}
+
+// MethodStats are the counters a Metrics keeps for an API method.
+type MethodStats struct {
+	Calls    int64
+	Errors   int64
+	Duration time.Duration
+}
+
+func (s MethodStats) String() string {
+	var str string
+
+	str += fmt.Sprintln("Calls:", s.Calls)
+	str += fmt.Sprintln("Errors:", s.Errors)
+	str += fmt.Sprintln("Duration:", s.Duration)
+
+	return str
+}
+
+// Metrics counts the calls, errors and total duration of every API method
+// sent through its Middleware. A request counts as an error if it failed or
+// the API returned an error.
+type Metrics struct {
+	lock    sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// NewMetrics creates a new, empty Metrics.
+func NewMetrics() *Metrics {
+	m := new(Metrics)
+	m.methods = make(map[string]*MethodStats)
+	return m
+}
+
+// Middleware returns a Middleware that records every request in the Metrics.
+func (m *Metrics) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *JSON2Request, header http.Header) (*JSON2Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req, header)
+			d := time.Since(start)
+
+			m.lock.Lock()
+			defer m.lock.Unlock()
+			s, ok := m.methods[req.Method]
+			if !ok {
+				s = new(MethodStats)
+				m.methods[req.Method] = s
+			}
+			s.Calls++
+			s.Duration += d
+			if err != nil || resp.Error != nil {
+				s.Errors++
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// Stats returns a copy of the counters of every method.
+func (m *Metrics) Stats() map[string]MethodStats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	stats := make(map[string]MethodStats)
+	for method, s := range m.methods {
+		stats[method] = *s
+	}
+	return stats
+}
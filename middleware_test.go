@@ -0,0 +1,132 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+func TestHeaderMiddleware(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Error("basic auth sent with an Authorization middleware")
+		}
+		rateHandler(w, r)
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Error("expected an error without the API key")
+	}
+
+	c.Middleware = []Middleware{
+		HeaderMiddleware("X-Api-Key", "secret"),
+		HeaderMiddleware("Authorization", "Bearer token"),
+	}
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoggingAndMetricsMiddleware(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(JSON2Request)
+		json.NewDecoder(r.Body).Decode(req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "entry-credit-rate" {
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"rate":1000}}`)
+		} else {
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"error":{"code":-32008,"message":"Entry not found"}}`)
+		}
+	}))
+	defer ts.Close()
+
+	buf := new(bytes.Buffer)
+	m := NewMetrics()
+	c := NewClient(ts.URL)
+	c.Middleware = []Middleware{LoggingMiddleware(log.New(buf, "", 0)), m.Middleware()}
+
+	c.GetECRate(context.Background())
+	c.GetECRate(context.Background())
+	c.GetEntry(context.Background(), "0000000000000000000000000000000000000000000000000000000000000000")
+
+	stats := m.Stats()
+	if s := stats["entry-credit-rate"]; s.Calls != 2 || s.Errors != 0 {
+		t.Errorf("unexpected entry-credit-rate stats:\n%s", s)
+	}
+	if s := stats["entry"]; s.Calls != 1 || s.Errors != 1 {
+		t.Errorf("unexpected entry stats:\n%s", s)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "method=entry-credit-rate ") {
+		t.Errorf("unexpected log line %q", lines[0])
+	}
+	if !strings.Contains(lines[2], `code=-32008 error="Entry not found"`) {
+		t.Errorf("unexpected log line %q", lines[2])
+	}
+}
+
+func TestMiddlewareBatch(t *testing.T) {
+	var batches int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		batches++
+		body, _ := ioutil.ReadAll(r.Body)
+		reqs := make([]*JSON2Request, 0)
+		json.Unmarshal(body, &reqs)
+		resps := make([]*JSON2Response, 0)
+		for _, req := range reqs {
+			resp := NewJSON2Response()
+			resp.ID = req.ID
+			resp.Result = json.RawMessage(`{"rate":1000}`)
+			resps = append(resps, resp)
+		}
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer ts.Close()
+
+	m := NewMetrics()
+	c := NewClient(ts.URL)
+	c.Middleware = []Middleware{HeaderMiddleware("X-Api-Key", "secret"), m.Middleware()}
+
+	reqs := make([]*JSON2Request, 10)
+	for i := range reqs {
+		reqs[i] = NewJSON2Request("entry-credit-rate", i, nil)
+	}
+	resps, err := c.SendBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resps) != 10 || batches != 1 {
+		t.Errorf("expected 10 responses in 1 batch, got %d in %d", len(resps), batches)
+	}
+	if s := m.Stats()["entry-credit-rate"]; s.Calls != 10 {
+		t.Errorf("expected every request of the batch in the metrics, got %d", s.Calls)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PoolStrategy selects the order in which a ServerPool tries its healthy
+// servers.
+type PoolStrategy int
+
+const (
+	// RoundRobin spreads requests evenly over the healthy servers.
+	RoundRobin PoolStrategy = iota
+	// BestNode sends requests to the healthy server with the highest
+	// Directory Block height.
+	BestNode
+)
+
+// ServerPool is a set of factomd servers that a Client sends its requests to.
+// The servers are checked with GetCurrentMinute every CheckInterval; servers
+// that do not answer, have detected a stall, or lag more than MaxLag blocks
+// behind are skipped while there is a healthy server to use. If a request to a
+// server fails, the request fails over to the next server.
+//
+// Only the first requests wait for the first health check. Later checks run
+// in the background while the requests use the last known status, so a slow
+// server does not hold up the requests to the others.
+//
+// A ServerPool should not be modified once it is in use; it is then safe for
+// concurrent use by multiple Clients.
+type ServerPool struct {
+	Servers  []string
+	Strategy PoolStrategy
+
+	// MaxLag is the number of Directory Blocks a server may fall behind the
+	// highest server, or behind its own leader height, before it is skipped.
+	// A negative MaxLag disables the check.
+	MaxLag int64
+
+	CheckInterval time.Duration
+	CheckTimeout  time.Duration
+
+	// checkLock serializes the health checks, lock guards the status and
+	// checking, which is closed once the running background check is done
+	checkLock sync.Mutex
+	lock      sync.Mutex
+	checked   time.Time
+	status    map[string]*NodeStatus
+	checking  chan struct{}
+	next      int
+}
+
+// NodeStatus is the result of the last health check of a server in a
+// ServerPool.
+type NodeStatus struct {
+	Server       string
+	Healthy      bool
+	Stalled      bool
+	DBHeight     int64
+	LeaderHeight int64
+	Checked      time.Time
+	Err          error
+}
+
+func (s *NodeStatus) String() string {
+	var str string
+
+	str += fmt.Sprintln("Server:", s.Server)
+	str += fmt.Sprintln("Healthy:", s.Healthy)
+	str += fmt.Sprintln("Stalled:", s.Stalled)
+	str += fmt.Sprintln("DBHeight:", s.DBHeight)
+	str += fmt.Sprintln("LeaderHeight:", s.LeaderHeight)
+	str += fmt.Sprintln("Checked:", s.Checked)
+	if s.Err != nil {
+		str += fmt.Sprintln("Error:", s.Err)
+	}
+
+	return str
+}
+
+// NewServerPool creates a new ServerPool of the given factomd servers.
+func NewServerPool(servers ...string) *ServerPool {
+	p := new(ServerPool)
+	p.Servers = servers
+	p.Strategy = RoundRobin
+	p.MaxLag = 2
+	p.CheckInterval = 30 * time.Second
+	p.CheckTimeout = 5 * time.Second
+	return p
+}
+
+// Status returns the result of the last health check of every server in the
+// pool.
+func (p *ServerPool) Status() []NodeStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ss := make([]NodeStatus, 0, len(p.Servers))
+	for _, server := range p.Servers {
+		if s, ok := p.status[server]; ok {
+			ss = append(ss, *s)
+		} else {
+			ss = append(ss, NodeStatus{Server: server})
+		}
+	}
+	return ss
+}
+
+// Check runs a health check of every server in the pool using the settings
+// of the Client.
+func (p *ServerPool) Check(ctx context.Context, c *Client) {
+	p.checkLock.Lock()
+	defer p.checkLock.Unlock()
+
+	p.check(ctx, c)
+}
+
+func (p *ServerPool) check(parent context.Context, c *Client) {
+	ctx := parent
+	if p.CheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.CheckTimeout)
+		defer cancel()
+	}
+
+	status := make(map[string]*NodeStatus)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, server := range p.Servers {
+		wg.Add(1)
+		go func(server string) {
+			defer wg.Done()
+			s := &NodeStatus{Server: server, Checked: time.Now()}
+			m, err := c.withServer(server).GetCurrentMinute(ctx)
+			if err != nil {
+				s.Err = err
+			} else {
+				s.Stalled = m.StallDetected
+				s.DBHeight = m.DirectoryBlockHeight
+				s.LeaderHeight = m.LeaderHeight
+			}
+			mu.Lock()
+			status[server] = s
+			mu.Unlock()
+		}(server)
+	}
+	wg.Wait()
+
+	// the results are meaningless if the caller gave up during the check
+	if parent.Err() != nil {
+		return
+	}
+
+	var top int64
+	for _, s := range status {
+		if s.Err == nil && s.DBHeight > top {
+			top = s.DBHeight
+		}
+	}
+	for _, s := range status {
+		s.Healthy = s.Err == nil && !s.Stalled
+		if s.Healthy && p.MaxLag >= 0 {
+			// a synced node builds the block after its last saved block
+			s.Healthy = top-s.DBHeight <= p.MaxLag &&
+				s.LeaderHeight-s.DBHeight-1 <= p.MaxLag
+		}
+	}
+
+	p.lock.Lock()
+	p.status = status
+	p.checked = time.Now()
+	p.lock.Unlock()
+}
+
+// backgroundCheck starts a health check in the background unless one is
+// already running, and returns the channel that is closed once it is done.
+// The caller must hold p.lock.
+func (p *ServerPool) backgroundCheck(c *Client) chan struct{} {
+	if p.checking != nil {
+		return p.checking
+	}
+
+	done := make(chan struct{})
+	p.checking = done
+	go func() {
+		p.Check(context.Background(), c)
+
+		p.lock.Lock()
+		p.checking = nil
+		p.lock.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// servers returns the servers in the order they should be tried. A health
+// check is started if the last one is out of date; the order is taken from
+// the last known status, unless there is none yet, in which case the first
+// health check is waited for.
+func (p *ServerPool) servers(ctx context.Context, c *Client) []string {
+	p.lock.Lock()
+	var done chan struct{}
+	if p.status == nil || time.Since(p.checked) >= p.CheckInterval {
+		done = p.backgroundCheck(c)
+	}
+	first := p.status == nil
+	p.lock.Unlock()
+
+	if first {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	healthy := make([]string, 0, len(p.Servers))
+	unhealthy := make([]string, 0)
+	for _, server := range p.Servers {
+		if s, ok := p.status[server]; ok && s.Healthy {
+			healthy = append(healthy, server)
+		} else {
+			unhealthy = append(unhealthy, server)
+		}
+	}
+
+	switch p.Strategy {
+	case BestNode:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return p.status[healthy[i]].DBHeight > p.status[healthy[j]].DBHeight
+		})
+	default:
+		if len(healthy) > 0 {
+			n := p.next % len(healthy)
+			healthy = append(healthy[n:], healthy[:n]...)
+			p.next++
+		}
+	}
+
+	// unhealthy servers are tried as a last resort
+	return append(healthy, unhealthy...)
+}
+
+// markDown marks a server as unhealthy until the next health check.
+func (p *ServerPool) markDown(server string, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if s, ok := p.status[server]; ok {
+		s.Healthy = false
+		s.Err = err
+	}
+}
+
+// post sends the request body to the servers of the pool in turn until one of
+// them answers.
+func (p *ServerPool) post(
+	ctx context.Context,
+	c *Client,
+	path string,
+	idempotent bool,
+	header http.Header,
+	j []byte,
+	decode func(io.Reader) error,
+) error {
+	var err error
+	for _, server := range p.servers(ctx, c) {
+		err = c.factomdPost(ctx, server, path, header, j, decode)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || !failover(err, idempotent) {
+			return err
+		}
+		p.markDown(server, err)
+	}
+	if err == nil {
+		err = errors.New("factomd server pool is empty")
+	}
+	return err
+}
+
+// failover returns true if a request that failed with err may be sent to the
+// next server. Requests that change the state of the network are only sent
+// again if the failed server was never reached.
+func failover(err error, idempotent bool) bool {
+	var oerr *net.OpError
+	if errors.As(err, &oerr) && oerr.Op == "dial" {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		return herr.StatusCode >= 500
+	}
+	return transient(err)
+}
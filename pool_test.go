@@ -0,0 +1,173 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+// poolNode is a factomd server for the pool tests that reports the given
+// heights from current-minute and counts the other requests it answers.
+type poolNode struct {
+	*httptest.Server
+	requests int32
+}
+
+func newPoolNode(dbheight int64, stalled bool) *poolNode {
+	n := new(poolNode)
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(JSON2Request)
+		json.NewDecoder(r.Body).Decode(req)
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case "current-minute":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":0,"result":{"directoryblockheight":%d,"leaderheight":%d,"stalldetected":%v}}`,
+				dbheight, dbheight+1, stalled)
+		default:
+			atomic.AddInt32(&n.requests, 1)
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"rate":1000}}`)
+		}
+	}))
+	return n
+}
+
+func TestServerPoolSkipsUnhealthyNodes(t *testing.T) {
+	good := newPoolNode(100, false)
+	defer good.Close()
+	stalled := newPoolNode(100, true)
+	defer stalled.Close()
+	lagging := newPoolNode(90, false)
+	defer lagging.Close()
+
+	c := NewClient("")
+	c.Pool = NewServerPool(stalled.URL, lagging.URL, good.URL)
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if good.requests != 5 || stalled.requests != 0 || lagging.requests != 0 {
+		t.Errorf("expected every request on the healthy node, got %d %d %d",
+			good.requests, stalled.requests, lagging.requests)
+	}
+
+	for _, s := range c.Pool.Status() {
+		if s.Healthy != (s.Server == good.URL) {
+			t.Errorf("unexpected health check result:\n%s", s.String())
+		}
+	}
+}
+
+func TestServerPoolStrategies(t *testing.T) {
+	low := newPoolNode(99, false)
+	defer low.Close()
+	high := newPoolNode(100, false)
+	defer high.Close()
+
+	c := NewClient("")
+	c.Pool = NewServerPool(low.URL, high.URL)
+	for i := 0; i < 10; i++ {
+		c.GetECRate(context.Background())
+	}
+	if low.requests != 5 || high.requests != 5 {
+		t.Errorf("expected round robin over both nodes, got %d %d", low.requests, high.requests)
+	}
+
+	c.Pool = NewServerPool(low.URL, high.URL)
+	c.Pool.Strategy = BestNode
+	for i := 0; i < 10; i++ {
+		c.GetECRate(context.Background())
+	}
+	if low.requests != 5 || high.requests != 15 {
+		t.Errorf("expected every request on the best node, got %d %d", low.requests, high.requests)
+	}
+}
+
+func TestServerPoolFailover(t *testing.T) {
+	down := newPoolNode(100, false)
+	up := newPoolNode(100, false)
+	defer up.Close()
+
+	c := NewClient("")
+	c.Pool = NewServerPool(down.URL, up.URL)
+	c.Pool.Check(context.Background(), c)
+
+	// the first node goes down after the health check
+	down.Close()
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if up.requests != 4 {
+		t.Errorf("expected 4 requests on the remaining node, got %d", up.requests)
+	}
+}
+
+func TestServerPoolBackgroundCheck(t *testing.T) {
+	// every health check after the first hangs until the end of the test
+	release := make(chan struct{})
+	var checks int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(JSON2Request)
+		json.NewDecoder(r.Body).Decode(req)
+		if req.Method == "current-minute" {
+			if atomic.AddInt32(&checks, 1) > 1 {
+				<-release
+			}
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"directoryblockheight":100,"leaderheight":101}}`)
+			return
+		}
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"rate":1000}}`)
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	c := NewClient("")
+	c.Pool = NewServerPool(slow.URL)
+	c.Pool.CheckInterval = time.Nanosecond
+	c.Pool.CheckTimeout = 10 * time.Second
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("expected the requests not to wait for the health check, took %v", d)
+	}
+	if n := atomic.LoadInt32(&checks); n > 2 {
+		t.Errorf("expected at most 2 health checks, got %d", n)
+	}
+}
+
+func TestServerPoolWrites(t *testing.T) {
+	reader := newPoolNode(100, false)
+	defer reader.Close()
+	writer := newPoolNode(100, false)
+	defer writer.Close()
+
+	c := NewClient("")
+	c.Pool = NewServerPool(reader.URL)
+	c.WritePool = NewServerPool(writer.URL)
+
+	c.GetECRate(context.Background())
+	c.SendRequest(context.Background(), NewJSON2Request("commit-entry", 0, nil))
+	if reader.requests != 1 || writer.requests != 1 {
+		t.Errorf("expected 1 read and 1 write, got %d %d", reader.requests, writer.requests)
+	}
+}
@@ -5,6 +5,7 @@
 package factom
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -47,14 +48,14 @@ func (p *Properties) String() string {
 
 // GetProperties requests various properties of the factomd and factom wallet
 // software and API versions.
-func GetProperties() (*Properties, error) {
+func (w *WalletClient) GetProperties(ctx context.Context) (*Properties, error) {
 	// get properties from the factom API and the wallet API
 	props := new(Properties)
 	// wprops := new(PropertiesResponse)
 	req := NewJSON2Request("properties", APICounter(), nil)
 	wreq := NewJSON2Request("properties", APICounter(), nil)
 
-	resp, err := factomdRequest(req)
+	resp, err := w.Factomd.factomdRequest(ctx, req)
 	if err != nil {
 		props.FactomdVersionErr = err.Error()
 		return props, err
@@ -65,7 +66,7 @@ func GetProperties() (*Properties, error) {
 		return props, jerr
 	}
 
-	wresp, werr := walletRequest(wreq)
+	wresp, werr := w.walletRequest(ctx, wreq)
 	wprops := new(Properties)
 	if werr != nil {
 		props.WalletVersionErr = werr.Error()
@@ -80,7 +81,20 @@ func GetProperties() (*Properties, error) {
 	props.WalletVersion = wprops.WalletVersion
 	props.WalletVersionErr = wprops.WalletVersionErr
 	props.WalletAPIVersion = wprops.WalletAPIVersion
-	props.WalletVersionErr = wprops.WalletAPIVersionErr
+	props.WalletAPIVersionErr = wprops.WalletAPIVersionErr
+
+	return props, nil
+}
+
+// GetWalletProperties requests the software and API versions of the factom
+// wallet alone, without the factomd properties.
+func (w *WalletClient) GetWalletProperties(ctx context.Context) (*Properties, error) {
+	req := NewJSON2Request("properties", APICounter(), nil)
+
+	props := new(Properties)
+	if err := w.walletCall(ctx, req, props); err != nil {
+		return nil, err
+	}
 
 	return props, nil
 }
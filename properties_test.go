@@ -5,6 +5,7 @@
 package factom_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -54,3 +55,21 @@ func TestGetProperties(t *testing.T) {
 	}
 	t.Log(props)
 }
+
+func TestGetWalletProperties(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":2,"result":{"walletversion":"2.2.15","walletapiversion":"2.0"}}`)
+	}))
+	defer ts.Close()
+
+	// factomd is not needed
+	w := NewWalletClient(ts.URL, NewClient("localhost:1"))
+	props, err := w.GetWalletProperties(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.WalletVersion != "2.2.15" || props.WalletAPIVersion != "2.0" || props.FactomdVersion != "" {
+		t.Errorf("unexpected wallet properties\n%s", props)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is the pause after a 429 response without a Retry-After
+// header.
+const defaultRetryAfter = time.Second
+
+// RateLimiter limits the requests a Client or WalletClient sends to its API
+// server to a rate of requests per second with bursts of up to burst
+// requests, and to at most maxInFlight requests at once. When the server
+// answers 429 Too Many Requests, every request sent through the RateLimiter
+// pauses for the time given by the Retry-After header and the request is sent
+// again.
+//
+// A RateLimiter may be shared by several clients; it is safe for concurrent
+// use by multiple goroutines.
+type RateLimiter struct {
+	// MaxThrottled is the number of times a request answered with 429 is
+	// sent again before the 429 is returned as an HTTPError.
+	MaxThrottled int
+
+	rate        float64
+	burst       int
+	maxInFlight int
+	inFlight    chan struct{}
+
+	lock        sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	waiting     int
+	requests    int64
+	throttled   int64
+}
+
+// NewRateLimiter creates a new RateLimiter. A rate of 0 does not limit the
+// rate of the requests, and a maxInFlight of 0 does not limit the number of
+// requests at once.
+func NewRateLimiter(rate float64, burst, maxInFlight int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := new(RateLimiter)
+	l.MaxThrottled = 10
+	l.rate = rate
+	l.burst = burst
+	l.maxInFlight = maxInFlight
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	l.tokens = float64(burst)
+	l.last = time.Now()
+	return l
+}
+
+// LimiterState is a snapshot of the state of a RateLimiter.
+type LimiterState struct {
+	Rate        float64
+	Burst       int
+	Tokens      float64
+	MaxInFlight int
+	InFlight    int
+	Waiting     int
+	PausedUntil time.Time
+	Requests    int64
+	Throttled   int64
+}
+
+func (s *LimiterState) String() string {
+	var str string
+
+	str += fmt.Sprintln("Rate:", s.Rate)
+	str += fmt.Sprintln("Burst:", s.Burst)
+	str += fmt.Sprintln("Tokens:", s.Tokens)
+	str += fmt.Sprintln("MaxInFlight:", s.MaxInFlight)
+	str += fmt.Sprintln("InFlight:", s.InFlight)
+	str += fmt.Sprintln("Waiting:", s.Waiting)
+	str += fmt.Sprintln("PausedUntil:", s.PausedUntil)
+	str += fmt.Sprintln("Requests:", s.Requests)
+	str += fmt.Sprintln("Throttled:", s.Throttled)
+
+	return str
+}
+
+// State returns the current state of the RateLimiter.
+func (l *RateLimiter) State() *LimiterState {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill(time.Now())
+	s := new(LimiterState)
+	s.Rate = l.rate
+	s.Burst = l.burst
+	s.Tokens = l.tokens
+	s.MaxInFlight = l.maxInFlight
+	s.InFlight = len(l.inFlight)
+	s.Waiting = l.waiting
+	s.PausedUntil = l.pausedUntil
+	s.Requests = l.requests
+	s.Throttled = l.throttled
+	return s
+}
+
+// Pause stops every request sent through the RateLimiter for d.
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// refill adds the tokens earned since the last refill.
+func (l *RateLimiter) refill(now time.Time) {
+	if l.rate <= 0 {
+		return
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before a
+// token is available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		l.requests++
+		return 0
+	}
+	l.refill(now)
+	if l.tokens >= 1 {
+		l.tokens--
+		l.requests++
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// acquire waits until a request may be sent and returns the function to call
+// once the request is done. A nil RateLimiter does not wait.
+func (l *RateLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.lock.Lock()
+	l.waiting++
+	l.lock.Unlock()
+	defer func() {
+		l.lock.Lock()
+		l.waiting--
+		l.lock.Unlock()
+	}()
+
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-l.inFlight }
+	}
+
+	for {
+		wait := l.reserve()
+		if wait == 0 {
+			return release, nil
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			release()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// do sends an HTTP request through the RateLimiter and passes the response to
+// read, which may read its body. A request answered with 429 pauses the
+// RateLimiter and is sent again, up to MaxThrottled times. A nil RateLimiter
+// sends the request once.
+func (l *RateLimiter) do(ctx context.Context, client *http.Client, re *http.Request, read func(*http.Response) error) error {
+	for throttled := 0; ; throttled++ {
+		release, err := l.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(re)
+		if err != nil {
+			release()
+			return err
+		}
+
+		if l == nil || resp.StatusCode != http.StatusTooManyRequests || throttled >= l.MaxThrottled {
+			err = read(resp)
+			resp.Body.Close()
+			release()
+			return err
+		}
+		// read what is left of a short body so the connection can be reused
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4<<10))
+		resp.Body.Close()
+		release()
+
+		l.lock.Lock()
+		l.throttled++
+		l.lock.Unlock()
+		d := retryAfter(resp.Header)
+		if d == 0 {
+			d = defaultRetryAfter
+		}
+		l.Pause(d)
+
+		// send the request again with a fresh body
+		re = re.Clone(ctx)
+		if re.GetBody != nil {
+			if re.Body, err = re.GetBody(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// retryAfter returns the wait given by a Retry-After header, in seconds or as
+// an HTTP date, or 0.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
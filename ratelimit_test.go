@@ -0,0 +1,136 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+func TestRateLimiterRate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(rateHandler))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.Limiter = NewRateLimiter(20, 1, 0)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the first request is free, the other 4 wait 50ms each
+	if d := time.Since(start); d < 190*time.Millisecond {
+		t.Errorf("expected the requests to take at least 200ms, took %v", d)
+	}
+	if s := c.Limiter.State(); s.Requests != 5 {
+		t.Errorf("expected 5 requests in the limiter state, got\n%s", s)
+	}
+
+	// a request waiting for a token gives up with its context
+	c.Limiter = NewRateLimiter(0.1, 1, 0)
+	c.GetECRate(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetECRate(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterInFlight(t *testing.T) {
+	var inFlight, max int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		rateHandler(w, r)
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.Limiter = NewRateLimiter(0, 1, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetECRate(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", max)
+	}
+}
+
+func TestRateLimiterTooManyRequests(t *testing.T) {
+	var posts, conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&posts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "slow down"+strings.Repeat(".", 3000), http.StatusTooManyRequests)
+			return
+		}
+		rateHandler(w, r)
+	}))
+	ts.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Fatal("expected a 429 error without a limiter")
+	} else {
+		var herr *HTTPError
+		if !errors.As(err, &herr) || herr.RetryAfter != time.Second {
+			t.Errorf("expected an HTTPError with a Retry-After of 1s, got %v", err)
+		}
+	}
+
+	atomic.StoreInt32(&posts, 0)
+	c.Limiter = NewRateLimiter(0, 1, 0)
+	start := time.Now()
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 900*time.Millisecond {
+		t.Errorf("expected a pause of 1s, took %v", d)
+	}
+	s := c.Limiter.State()
+	if s.Throttled != 1 || s.PausedUntil.IsZero() {
+		t.Errorf("expected a throttled request in the limiter state, got\n%s", s)
+	}
+
+	// the connection is reused after a 429
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("expected 1 connection, got %d", n)
+	}
+}
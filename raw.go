@@ -5,8 +5,8 @@
 package factom
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 )
 
 // RawData is a simple hex encoded byte string
@@ -20,19 +20,11 @@ func (r *RawData) GetDataBytes() ([]byte, error) {
 
 // GetRaw requests the raw data for any binary block kept in the factomd
 // database.
-func GetRaw(keymr string) ([]byte, error) {
+func (c *Client) GetRaw(ctx context.Context, keymr string) ([]byte, error) {
 	params := hashRequest{Hash: keymr}
 	req := NewJSON2Request("raw-data", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	raw := new(RawData)
-	if err := json.Unmarshal(resp.JSONResult(), raw); err != nil {
+	if err := c.factomdCall(ctx, req, raw); err != nil {
 		return nil, err
 	}
 
@@ -41,21 +33,13 @@ func GetRaw(keymr string) ([]byte, error) {
 
 // SendRawMsg sends a raw hex encoded byte string for factomd to send as a
 // binary message on the Factom Netwrork.
-func SendRawMsg(message string) (string, error) {
+func (c *Client) SendRawMsg(ctx context.Context, message string) (string, error) {
 	param := messageRequest{Message: message}
 	req := NewJSON2Request("send-raw-message", APICounter(), param)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != nil {
-		return "", resp.Error
-	}
-
 	status := new(struct {
 		Message string `json:"message"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), status); err != nil {
+	if err := c.factomdCall(ctx, req, status); err != nil {
 		return "", err
 	}
 
@@ -5,10 +5,18 @@
 package factom
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 )
 
+var (
+	ErrInvalidReceipt  = errors.New("invalid receipt")
+	ErrReceiptMismatch = errors.New("receipt does not prove the trusted Directory Block KeyMR")
+)
+
 // Receipt is the Merkel proof that a given Entry and its metadata (such as the
 // Entry Block timestamp) have been written to the Factom Blockchain and
 // possibly anchored into Bitcoin, Etherium, or other blockchains.
@@ -62,24 +70,109 @@ func (r *Receipt) String() string {
 	return s
 }
 
+// Verify recomputes the Merkle proof of the Receipt from the Entry Hash
+// through the Entry Block KeyMR up to the Directory Block KeyMR. Each step of
+// the MerkleBranch must include the result of the step before it and have the
+// hash of its left and right nodes as its top. If the Receipt includes the raw
+// Entry it must hash to the Entry Hash.
+//
+// Verify only proves that the Receipt is consistent. Use VerifyKeyMR to also
+// prove that the Entry is in a Directory Block that is already trusted.
+func (r *Receipt) Verify() error {
+	entryHash, err := receiptHash(r.Entry.EntryHash, "EntryHash")
+	if err != nil {
+		return err
+	}
+	if r.Entry.Raw != "" {
+		raw, err := hex.DecodeString(r.Entry.Raw)
+		if err != nil {
+			return fmt.Errorf("%w: Raw: %v", ErrInvalidReceipt, err)
+		}
+		e := new(Entry)
+		if err := e.UnmarshalBinary(raw); err != nil {
+			return fmt.Errorf("%w: Raw: %v", ErrInvalidReceipt, err)
+		}
+		if !bytes.Equal(e.Hash(), entryHash) {
+			return fmt.Errorf("%w: Raw Entry hashes to %x, not the EntryHash",
+				ErrInvalidReceipt, e.Hash())
+		}
+	}
+	ebKeyMR, err := receiptHash(r.EntryBlockKeyMR, "EntryBlockKeyMR")
+	if err != nil {
+		return err
+	}
+	dbKeyMR, err := receiptHash(r.DirectoryBlockKeyMR, "DirectoryBlockKeyMR")
+	if err != nil {
+		return err
+	}
+	if len(r.MerkleBranch) == 0 {
+		return fmt.Errorf("%w: MerkleBranch is empty", ErrInvalidReceipt)
+	}
+
+	node := entryHash
+	foundEBlock := false
+	for i, b := range r.MerkleBranch {
+		top, err := merkleStep(node, b.Left, b.Right, b.Top)
+		if err != nil {
+			return fmt.Errorf("%w: MerkleBranch[%d] %v", ErrInvalidReceipt, i, err)
+		}
+		if bytes.Equal(top, ebKeyMR) {
+			foundEBlock = true
+		}
+		node = top
+	}
+
+	if !foundEBlock {
+		return fmt.Errorf("%w: MerkleBranch does not include the EntryBlockKeyMR",
+			ErrInvalidReceipt)
+	}
+	if !bytes.Equal(node, dbKeyMR) {
+		return fmt.Errorf("%w: MerkleBranch ends in %x, not the DirectoryBlockKeyMR",
+			ErrInvalidReceipt, node)
+	}
+
+	return nil
+}
+
+// VerifyKeyMR verifies the Receipt and that it proves the Directory Block
+// KeyMR dbKeyMR, which the caller already trusts, e.g. from an anchor or a
+// Directory Block it has validated itself.
+func (r *Receipt) VerifyKeyMR(dbKeyMR string) error {
+	if err := r.Verify(); err != nil {
+		return err
+	}
+	trusted, err := hex.DecodeString(dbKeyMR)
+	if err != nil {
+		return fmt.Errorf("dbKeyMR: %w", err)
+	}
+	if proven, _ := hex.DecodeString(r.DirectoryBlockKeyMR); !bytes.Equal(proven, trusted) {
+		return fmt.Errorf("%w: receipt proves %s", ErrReceiptMismatch, r.DirectoryBlockKeyMR)
+	}
+	return nil
+}
+
+// receiptHash decodes a 32 byte hex hash of a Receipt.
+func receiptHash(h string, field string) ([]byte, error) {
+	p, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidReceipt, field, err)
+	}
+	if len(p) != 32 {
+		return nil, fmt.Errorf("%w: %s is %d bytes, not 32", ErrInvalidReceipt, field, len(p))
+	}
+	return p, nil
+}
+
 // GetReceipt requests a Receipt for a given Factom Entry.
-func GetReceipt(hash string) (*Receipt, error) {
+func (c *Client) GetReceipt(ctx context.Context, hash string) (*Receipt, error) {
 	type receiptResponse struct {
 		Receipt *Receipt `json:"receipt"`
 	}
 
 	params := hashRequest{Hash: hash}
 	req := NewJSON2Request("receipt", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	rec := new(receiptResponse)
-	if err := json.Unmarshal(resp.JSONResult(), rec); err != nil {
+	if err := c.factomdCall(ctx, req, rec); err != nil {
 		return nil, err
 	}
 
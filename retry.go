@@ -0,0 +1,186 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// HTTPError is returned when an API server answers with an HTTP error status
+// and a body that is not a JSON RPC response, as a proxy in front of factomd
+// may do.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+
+	// RetryAfter is the wait asked for by the Retry-After header, or 0.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("API server returned HTTP status %s", e.Status)
+}
+
+// RetryPolicy configures how a Client retries factomd requests that fail with
+// a transient error, such as a dropped connection, a timeout, or a 502 from a
+// proxy. Errors returned by factomd in the JSON RPC response are never
+// retried.
+//
+// Requests for methods that change the state of the network (see
+// IsIdempotentMethod) are only retried if RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. The wait doubles
+	// after every attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of each wait that is
+	// randomized so that clients do not retry in lockstep.
+	Jitter float64
+
+	// RetryableStatus lists the HTTP status codes that are retried.
+	RetryableStatus []int
+
+	// RetryNonIdempotent allows requests such as commit-entry and
+	// factoid-submit to be retried. A retried commit may be processed twice
+	// by the network.
+	RetryNonIdempotent bool
+}
+
+// NewRetryPolicy returns a RetryPolicy with sensible defaults for the public
+// factomd API servers.
+func NewRetryPolicy() *RetryPolicy {
+	p := new(RetryPolicy)
+	p.MaxAttempts = 3
+	p.InitialBackoff = 200 * time.Millisecond
+	p.MaxBackoff = 5 * time.Second
+	p.Jitter = 0.5
+	p.RetryableStatus = []int{
+		http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+	return p
+}
+
+// nonIdempotentMethods are the factomd API methods that change the state of
+// the network, or of the node for the debug API, and may not be safely sent
+// twice.
+var nonIdempotentMethods = map[string]bool{
+	"commit-chain":     true,
+	"commit-entry":     true,
+	"reveal-chain":     true,
+	"reveal-entry":     true,
+	"factoid-submit":   true,
+	"send-raw-message": true,
+
+	"reload-configuration": true,
+	"set-delay":            true,
+	"set-drop-rate":        true,
+}
+
+// IsIdempotentMethod returns true if a factomd API method may be safely sent
+// more than once.
+func IsIdempotentMethod(method string) bool {
+	return !nonIdempotentMethods[method]
+}
+
+// Retryable returns true if a request that failed with err should be sent
+// again under the policy.
+func (p *RetryPolicy) Retryable(err error) bool {
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		for _, code := range p.RetryableStatus {
+			if herr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	return transient(err)
+}
+
+// transient returns true if err is a network error that may not happen again,
+// such as a dropped connection or a timeout.
+func transient(err error) bool {
+	if errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var nerr net.Error
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// Backoff returns the wait before the given retry, counting from 1.
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(2, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// retry calls f until it succeeds, fails with an error that is not retryable,
+// or the policy runs out of attempts. A nil policy calls f once.
+func (p *RetryPolicy) retry(
+	ctx context.Context,
+	idempotent bool,
+	f func() error,
+) error {
+	if p == nil || (!idempotent && !p.RetryNonIdempotent) {
+		return f()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.MaxAttempts || !p.Retryable(err) {
+			return err
+		}
+		// the caller gave up, so the error was not transient
+		if ctx.Err() != nil {
+			return err
+		}
+
+		// wait at least as long as the server asked for
+		d := p.Backoff(attempt)
+		var herr *HTTPError
+		if errors.As(err, &herr) && herr.RetryAfter > d {
+			d = herr.RetryAfter
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+// flakyServer fails the first n requests with a 502 from a proxy and then
+// answers every request with result.
+func flakyServer(n int32, result string, posts *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(posts, 1) <= n {
+			http.Error(w, "502 Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":0,"result":%s}`, result)
+	}))
+}
+
+func testRetryPolicy() *RetryPolicy {
+	p := NewRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestRetryReads(t *testing.T) {
+	var posts int32
+	ts := flakyServer(2, `{"rate":1000}`, &posts)
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	_, err := c.GetECRate(context.Background())
+	var herr *HTTPError
+	if !errors.As(err, &herr) || herr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected a 502 HTTPError without a retry policy, got %v", err)
+	}
+
+	atomic.StoreInt32(&posts, 0)
+	c.Retry = testRetryPolicy()
+	rate, err := c.GetECRate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 1000 {
+		t.Errorf("expected rate 1000, got %d", rate)
+	}
+	if posts != 3 {
+		t.Errorf("expected 3 requests, made %d", posts)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var posts int32
+	ts := flakyServer(2, `{"message":"Entry Commit Success","txid":"abcd"}`, &posts)
+	defer ts.Close()
+
+	e := NewEntryFromStrings(
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"content",
+	)
+	ec, _ := GetECAddress("Es2Rf7iM6PdsqfYCo3D1tnAR65SkLENyWJG1deUzpRMQmbh9F3eG")
+
+	c := NewClient(ts.URL)
+	c.Retry = testRetryPolicy()
+	if _, err := c.CommitEntry(context.Background(), e, ec); err == nil {
+		t.Error("expected commit-entry not to be retried")
+	}
+	if posts != 1 {
+		t.Errorf("expected 1 request, made %d", posts)
+	}
+
+	atomic.StoreInt32(&posts, 0)
+	c.Retry.RetryNonIdempotent = true
+	txid, err := c.CommitEntry(context.Background(), e, ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txid != "abcd" {
+		t.Errorf("expected txid abcd, got %s", txid)
+	}
+	if posts != 3 {
+		t.Errorf("expected 3 requests, made %d", posts)
+	}
+}
+
+func TestRetryDebugMethods(t *testing.T) {
+	var posts int32
+	ts := flakyServer(1000, `{}`, &posts)
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.Retry = testRetryPolicy()
+	calls := map[string]func() error{
+		"set-drop-rate": func() error {
+			_, err := c.SetDropRate(context.Background(), 10)
+			return err
+		},
+		"set-delay": func() error {
+			_, err := c.SetDelay(context.Background(), 100)
+			return err
+		},
+		"reload-configuration": func() error {
+			_, err := c.ReloadConfiguration(context.Background())
+			return err
+		},
+	}
+	for method, call := range calls {
+		if IsIdempotentMethod(method) {
+			t.Errorf("%s: expected the method not to be idempotent", method)
+		}
+		atomic.StoreInt32(&posts, 0)
+		if err := call(); err == nil {
+			t.Errorf("%s: expected an error", method)
+		}
+		if posts != 1 {
+			t.Errorf("%s: expected 1 request, made %d", method, posts)
+		}
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	var posts int32
+	ts := flakyServer(100, `{}`, &posts)
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.Retry = NewRetryPolicy()
+	c.Retry.MaxAttempts = 100
+	c.Retry.InitialBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetHeights(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if posts != 1 {
+		t.Errorf("expected 1 request, made %d", posts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := NewRetryPolicy()
+	p.InitialBackoff = 100 * time.Millisecond
+	p.MaxBackoff = time.Second
+	p.Jitter = 0
+
+	for retry, expected := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second,
+	} {
+		if d := p.Backoff(retry); d != expected {
+			t.Errorf("retry %d: expected backoff %v, got %v", retry, expected, d)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := p.Backoff(2); d < 100*time.Millisecond || d > 200*time.Millisecond {
+			t.Errorf("jittered backoff %v out of range", d)
+		}
+	}
+}
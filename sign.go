@@ -1,7 +1,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 )
 
 type Signature struct {
@@ -12,7 +12,7 @@ type Signature struct {
 // SignData lets you sign arbitrary data by the specified signer.
 // The signer can be either an FA address, EC address, or Identity.
 // Be aware that the data is transmitted to the wallet.
-func SignData(signer string, data []byte) (*Signature, error) {
+func (w *WalletClient) SignData(ctx context.Context, signer string, data []byte) (*Signature, error) {
 	params := &struct {
 		Signer string `json:"signer"`
 		Data   []byte `json:"data"`
@@ -22,16 +22,8 @@ func SignData(signer string, data []byte) (*Signature, error) {
 	}
 
 	req := NewJSON2Request("sign-data", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	sig := new(Signature)
-	if err := json.Unmarshal(resp.Result, sig); err != nil {
+	if err := w.walletCall(ctx, req, sig); err != nil {
 		return nil, err
 	}
 	return sig, nil
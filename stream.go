@@ -0,0 +1,181 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrResponseTooLarge is returned when the body of an API response is larger
+// than the MaxResponseSize of the Client or WalletClient.
+var ErrResponseTooLarge = errors.New("API response too large")
+
+// DefaultMaxResponseSize is the largest response body in bytes that is read
+// from an API server when the MaxResponseSize of the Client or WalletClient
+// is 0.
+const DefaultMaxResponseSize = 64 << 20
+
+// resultKey is the context key of the streamTarget of a request.
+type resultKey struct{}
+
+// streamTarget is the value the result of a request is decoded into while the
+// response body is read, without holding the raw result in memory.
+type streamTarget struct {
+	req    *JSON2Request
+	result interface{}
+}
+
+// withResult returns a context that has the result of req decoded straight
+// from the response body into result.
+func withResult(ctx context.Context, req *JSON2Request, result interface{}) context.Context {
+	return context.WithValue(ctx, resultKey{}, &streamTarget{req: req, result: result})
+}
+
+// responseDecoder returns the function that decodes the body of the response
+// to req into resp. If the context has a streamTarget for req, the result is
+// decoded into the target and resp.Result is left empty; other requests sent
+// with the same context, such as by a Middleware, are decoded as usual.
+func responseDecoder(ctx context.Context, req *JSON2Request, resp *JSON2Response) func(io.Reader) error {
+	t, ok := ctx.Value(resultKey{}).(*streamTarget)
+	if !ok || t.req != req {
+		return func(r io.Reader) error {
+			return json.NewDecoder(r).Decode(resp)
+		}
+	}
+
+	return func(r io.Reader) error {
+		s := struct {
+			JSONRPC string      `json:"jsonrpc"`
+			ID      interface{} `json:"id"`
+			Error   *JSONError  `json:"error"`
+			Result  interface{} `json:"result"`
+		}{Result: t.result}
+		if err := json.NewDecoder(r).Decode(&s); err != nil {
+			return err
+		}
+		resp.JSONRPC = s.JSONRPC
+		resp.ID = s.ID
+		resp.Error = s.Error
+		return nil
+	}
+}
+
+// readAll returns a decode function that reads the whole body into body.
+func readAll(body *[]byte) func(io.Reader) error {
+	return func(r io.Reader) error {
+		b, err := ioutil.ReadAll(r)
+		*body = b
+		return err
+	}
+}
+
+// readResponse passes the body of an API response to decode, reading at most
+// max bytes of it, or DefaultMaxResponseSize if max is 0. A negative max does
+// not limit the body. Responses other than 200 OK are only decoded if they
+// hold JSON, such as a JSON RPC error; otherwise an HTTPError is returned.
+func readResponse(resp *http.Response, max int64, decode func(io.Reader) error) error {
+	if max == 0 {
+		max = DefaultMaxResponseSize
+	}
+	var body io.Reader = resp.Body
+	if max > 0 {
+		body = &limitedReader{r: body, n: max, max: max}
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if err := decode(body); err != nil {
+			return err
+		}
+		// drain the rest, such as a trailing newline, so the connection is
+		// reused
+		_, err := io.Copy(ioutil.Discard, body)
+		return err
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || !json.Valid(data) {
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: retryAfter(resp.Header),
+		}
+	}
+	return decode(bytes.NewReader(data))
+}
+
+// limitedReader reads at most max bytes from r and returns ErrResponseTooLarge
+// if r holds more.
+type limitedReader struct {
+	r   io.Reader
+	n   int64
+	max int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.max)
+	}
+	// read one byte more than allowed to find out if there is more
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n - 1, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.max)
+	}
+	return n, err
+}
+
+// factomdCall sends a JSON RPC request to the factomd API server and decodes
+// its result into result. The result is decoded straight from the response
+// body, unless it is to be cached.
+func (c *Client) factomdCall(ctx context.Context, req *JSON2Request, result interface{}) error {
+	if _, ok := cacheKey(req); c.Cache == nil || !ok {
+		ctx = withResult(ctx, req, result)
+	}
+	resp, err := c.factomdRequest(ctx, req)
+	return callResult(resp, err, result)
+}
+
+// debugCall sends a JSON RPC request to the factomd debug API and decodes its
+// result straight from the response body into result.
+func (c *Client) debugCall(ctx context.Context, req *JSON2Request, result interface{}) error {
+	resp, err := c.debugRequest(withResult(ctx, req, result), req)
+	return callResult(resp, err, result)
+}
+
+// walletCall sends a JSON RPC request to the factom wallet API server and
+// decodes its result straight from the response body into result.
+func (w *WalletClient) walletCall(ctx context.Context, req *JSON2Request, result interface{}) error {
+	resp, err := w.walletRequest(withResult(ctx, req, result), req)
+	return callResult(resp, err, result)
+}
+
+// callResult returns the error of a call, or decodes the result of its
+// response into result if the result was not already streamed into it, such
+// as a cached result.
+func callResult(resp *JSON2Response, err error, result interface{}) error {
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.Result != nil {
+		return json.Unmarshal(resp.Result, result)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+// pendingEntriesHandler answers pending-entries with n entries.
+func pendingEntriesHandler(n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":0,"result":[`)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"entryhash":"%064x","status":"TransactionACK"}`, i)
+		}
+		fmt.Fprintln(w, `]}`)
+	}
+}
+
+func TestStreamedResult(t *testing.T) {
+	ts := httptest.NewServer(pendingEntriesHandler(5000))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	pending, err := c.GetPendingEntries(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 5000 {
+		t.Fatalf("expected 5000 pending entries, got %d", len(pending))
+	}
+	if pending[4999].EntryHash != fmt.Sprintf("%064x", 4999) || pending[0].Status != "TransactionACK" {
+		t.Errorf("unexpected pending entries %v %v", pending[0], pending[4999])
+	}
+
+	// middleware still sees the streamed requests
+	m := NewMetrics()
+	c.Middleware = []Middleware{m.Middleware()}
+	if _, err := c.GetPendingEntries(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s := m.Stats()["pending-entries"]; s.Calls != 1 {
+		t.Errorf("expected one pending-entries request in the metrics, got %v", s)
+	}
+}
+
+func TestStreamedError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"error":{"code":-32008,"message":"Block not found"}}`)
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	if _, err := c.GetFBlock(context.Background(), "00"); !errors.Is(err, ErrBlockNotFound) {
+		t.Errorf("expected ErrBlockNotFound, got %v", err)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	ts := httptest.NewServer(pendingEntriesHandler(100))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.MaxResponseSize = 1000
+	if _, err := c.GetPendingEntries(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	// the limit holds for error responses and batches too
+	ts500 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, strings.Repeat("x", 2000), http.StatusInternalServerError)
+	}))
+	defer ts500.Close()
+	c = NewClient(ts500.URL)
+	c.MaxResponseSize = 1000
+	if _, err := c.GetECRate(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+	reqs := []*JSON2Request{NewJSON2Request("properties", 1, nil)}
+	if _, err := c.SendBatch(context.Background(), reqs); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	// a response of exactly the limit is read
+	body := `{"jsonrpc":"2.0","id":0,"result":{"rate":1000}}`
+	tsExact := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer tsExact.Close()
+	c = NewClient(tsExact.URL)
+	c.MaxResponseSize = int64(len(body))
+	if rate, err := c.GetECRate(context.Background()); err != nil || rate != 1000 {
+		t.Errorf("expected a rate of 1000, got %d %v", rate, err)
+	}
+}
+
+func TestDefaultMaxResponseSize(t *testing.T) {
+	if c := NewClient("localhost:8088"); c.MaxResponseSize != DefaultMaxResponseSize {
+		t.Errorf("expected a Client limit of %d, got %d", DefaultMaxResponseSize, c.MaxResponseSize)
+	}
+	if w := NewWalletClient("localhost:8089", nil); w.MaxResponseSize != DefaultMaxResponseSize {
+		t.Errorf("expected a WalletClient limit of %d, got %d", DefaultMaxResponseSize, w.MaxResponseSize)
+	}
+
+	// an error page of more than DefaultMaxResponseSize bytes
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		page := []byte(strings.Repeat("x", 1<<20))
+		for i := 0; i <= DefaultMaxResponseSize>>20; i++ {
+			w.Write(page)
+		}
+	}))
+	defer ts.Close()
+
+	// a Client with a MaxResponseSize of 0 uses the default
+	c := &Client{Server: ts.URL}
+	if _, err := c.GetECRate(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	// a negative MaxResponseSize reads the whole page
+	c.MaxResponseSize = -1
+	var herr *HTTPError
+	if _, err := c.GetECRate(context.Background()); !errors.As(err, &herr) {
+		t.Errorf("expected an HTTPError without a limit, got %v", err)
+	}
+}
+
+func TestStreamedWalletResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"transactions":[{"txid":"01"},{"txid":"02"}]}}`)
+	}))
+	defer ts.Close()
+
+	w := NewWalletClient(ts.URL, nil)
+	txs, err := w.ListTransactionsAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 || txs[1].TxID != "02" {
+		t.Errorf("unexpected transactions %v", txs)
+	}
+
+	w.MaxResponseSize = 10
+	if _, err := w.ListTransactionsAll(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
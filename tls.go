@@ -0,0 +1,164 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ErrPinMismatch is returned when none of the certificates presented by an API
+// server match the pinned public keys of its TLSConfig.
+var ErrPinMismatch = errors.New("server certificate does not match a pinned public key")
+
+// ErrInsecureServer is returned when TLS is enabled for an API server that is
+// given with an explicit http:// prefix.
+var ErrInsecureServer = errors.New("http server with TLS enabled")
+
+// TLSConfig holds the TLS settings for connecting to a factomd or
+// factom-walletd API server. Setting the TLSConfig of a Client or
+// WalletClient enables TLS.
+//
+// A TLSConfig should not be modified once it is in use; the files it names
+// are read once, when the first request is sent. Call ResetTransports to read
+// them again, such as after rotating a certificate.
+type TLSConfig struct {
+	// CAFiles are PEM files of certificate authorities that are trusted in
+	// addition to the system roots, or instead of them if NoSystemRoots is
+	// set.
+	CAFiles       []string
+	NoSystemRoots bool
+
+	// ClientCertFile and ClientKeyFile are the PEM certificate and key
+	// presented to servers that require mutual TLS.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// ServerName overrides the name used for SNI and to verify the server
+	// certificate, for servers reached by an address that is not in their
+	// certificate.
+	ServerName string
+
+	// MinVersion is the minimum TLS version accepted, such as
+	// tls.VersionTLS12. The crypto/tls default is used if it is 0.
+	MinVersion uint16
+
+	// PinnedSPKI are the SPKIHash of the public keys the server certificate
+	// chain must include one of. Pins are only checked if there are any.
+	PinnedSPKI []string
+}
+
+// SPKIHash returns the base64 encoded sha256 hash of the Subject Public Key
+// Info of a certificate, as used by TLSConfig.PinnedSPKI.
+func SPKIHash(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// Validate checks that every file named by the TLSConfig can be read and
+// holds what is expected.
+func (t *TLSConfig) Validate() error {
+	_, err := t.tlsConfig("")
+	return err
+}
+
+// tlsConfig builds the crypto/tls configuration. The legacy certificate file
+// of the Client or WalletClient, if any, is trusted as an extra CA.
+func (t *TLSConfig) tlsConfig(certFile string) (*tls.Config, error) {
+	c := new(tls.Config)
+	c.ServerName = t.ServerName
+	c.MinVersion = t.MinVersion
+
+	caFiles := t.CAFiles
+	if certFile != "" {
+		caFiles = append(append([]string(nil), caFiles...), certFile)
+	}
+	if len(caFiles) > 0 || t.NoSystemRoots {
+		pool := x509.NewCertPool()
+		if !t.NoSystemRoots {
+			sys, err := x509.SystemCertPool()
+			if err != nil {
+				return nil, err
+			}
+			pool = sys
+		}
+		for _, f := range caFiles {
+			pem, err := ioutil.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", f)
+			}
+		}
+		c.RootCAs = pool
+	}
+
+	if t.ClientCertFile != "" || t.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.ClientCertFile, t.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(t.PinnedSPKI) > 0 {
+		pins := make(map[string]bool)
+		for _, p := range t.PinnedSPKI {
+			pins[strings.TrimPrefix(p, "sha256/")] = true
+		}
+		// the verified chains run from the server certificate to a trusted
+		// root, which servers do not send themselves
+		c.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			for _, chain := range verifiedChains {
+				for _, cert := range chain {
+					if pins[SPKIHash(cert)] {
+						return nil
+					}
+				}
+			}
+
+			// nothing is verified if verification is skipped, so only the
+			// certificates sent by the server can be checked
+			if c.InsecureSkipVerify {
+				for _, raw := range rawCerts {
+					cert, err := x509.ParseCertificate(raw)
+					if err != nil {
+						return err
+					}
+					if pins[SPKIHash(cert)] {
+						return nil
+					}
+				}
+			}
+			return ErrPinMismatch
+		}
+	}
+
+	return c, nil
+}
+
+// serverURL returns the URL of the API at path of a server. An explicit
+// http:// or https:// prefix of the server is used as is; otherwise https is
+// used if TLS is enabled. An http:// server is refused if TLS is enabled,
+// rather than sending the request in plaintext.
+func serverURL(server string, tlsEnable bool, path string) (string, error) {
+	if strings.Contains(server, "://") {
+		if tlsEnable && strings.HasPrefix(strings.ToLower(server), "http://") {
+			return "", fmt.Errorf("%w: %s", ErrInsecureServer, server)
+		}
+		return strings.TrimSuffix(server, "/") + path, nil
+	}
+	if tlsEnable {
+		return "https://" + server + path, nil
+	}
+	return "http://" + server + path, nil
+}
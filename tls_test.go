@@ -0,0 +1,329 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+// writeServerCert writes the certificate of a TLS test server to a PEM file.
+func writeServerCert(t *testing.T, dir string, ts *httptest.Server) string {
+	path := filepath.Join(dir, "server.cert")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeClientCert writes a new self signed client certificate and its key to
+// PEM files and returns the certificate.
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "factom client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "client.cert")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return cert, certFile, keyFile
+}
+
+// newTLSServerWithCA starts a TLS test server whose certificate is issued by a
+// new CA. The server only sends its own certificate, not the CA.
+func newTLSServerWithCA(t *testing.T, dir string) (*httptest.Server, *x509.Certificate, string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "factom test CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageCertSign,
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "factomd"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(rateHandler))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	ts.StartTLS()
+
+	caFile := filepath.Join(dir, "ca.cert")
+	if err := ioutil.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return ts, ca, caFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert, certFile, keyFile := writeClientCert(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(rateHandler))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+	defer ts.Close()
+	caFile := writeServerCert(t, dir, ts)
+
+	// the server address is given without its scheme, TLS is enabled by the
+	// TLSConfig
+	c := NewClient(strings.TrimPrefix(ts.URL, "https://"))
+	c.TLS = &TLSConfig{CAFiles: []string{caFile}}
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Error("expected an error without a client certificate")
+	}
+
+	c = NewClient(strings.TrimPrefix(ts.URL, "https://"))
+	c.TLS = &TLSConfig{
+		CAFiles:        []string{caFile},
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+		MinVersion:     tls.VersionTLS12,
+	}
+	if rate, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	} else if rate != 1000 {
+		t.Errorf("expected a rate of 1000, got %d", rate)
+	}
+
+	// walletd has its own settings, and its server may carry a scheme
+	w := NewWalletClient(ts.URL, nil)
+	w.TLS = &TLSConfig{CAFiles: []string{caFile}, ClientCertFile: certFile, ClientKeyFile: keyFile}
+	if _, err := w.SendRequest(context.Background(), NewJSON2Request("properties", 0, nil)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTLSServerNameAndPins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(rateHandler))
+	defer ts.Close()
+	caFile := writeServerCert(t, dir, ts)
+	pin := SPKIHash(ts.Certificate())
+
+	tests := []struct {
+		Name string
+		TLS  *TLSConfig
+		Err  bool
+	}{
+		{"extra CA", &TLSConfig{CAFiles: []string{caFile}}, false},
+		{"system roots only", &TLSConfig{}, true},
+		{"CA only, no system roots", &TLSConfig{CAFiles: []string{caFile}, NoSystemRoots: true}, false},
+		// the test certificate is valid for example.com
+		{"server name", &TLSConfig{CAFiles: []string{caFile}, ServerName: "example.com"}, false},
+		{"wrong server name", &TLSConfig{CAFiles: []string{caFile}, ServerName: "factom.test"}, true},
+		{"pin", &TLSConfig{CAFiles: []string{caFile}, PinnedSPKI: []string{"sha256/" + pin}}, false},
+	}
+	for _, test := range tests {
+		c := NewClient(ts.URL)
+		c.TLS = test.TLS
+		_, err := c.GetECRate(context.Background())
+		if test.Err && err == nil {
+			t.Errorf("%s: expected an error", test.Name)
+		} else if !test.Err && err != nil {
+			t.Errorf("%s: %v", test.Name, err)
+		}
+	}
+
+	c := NewClient(ts.URL)
+	c.TLS = &TLSConfig{CAFiles: []string{caFile}, PinnedSPKI: []string{"AAAA"}}
+	if _, err := c.GetECRate(context.Background()); !errors.Is(err, ErrPinMismatch) {
+		t.Errorf("expected ErrPinMismatch, got %v", err)
+	}
+}
+
+func TestWalletSchemePrefix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(rateHandler))
+	defer ts.Close()
+
+	w := NewWalletClient(ts.URL, nil)
+	if _, err := w.SendRequest(context.Background(), NewJSON2Request("properties", 0, nil)); err != nil {
+		t.Error(err)
+	}
+
+	w = NewWalletClient(strings.TrimPrefix(ts.URL, "http://"), nil)
+	if _, err := w.SendRequest(context.Background(), NewJSON2Request("properties", 0, nil)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTLSRefusesHTTPServer(t *testing.T) {
+	var posts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		rateHandler(w, r)
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL)
+	c.TLS = &TLSConfig{}
+	if _, err := c.GetECRate(context.Background()); !errors.Is(err, ErrInsecureServer) {
+		t.Errorf("expected ErrInsecureServer, got %v", err)
+	}
+
+	w := NewWalletClient(ts.URL, nil)
+	w.TLSEnable = true
+	if _, err := w.SendRequest(context.Background(), NewJSON2Request("properties", 0, nil)); !errors.Is(err, ErrInsecureServer) {
+		t.Errorf("expected ErrInsecureServer, got %v", err)
+	}
+
+	if posts != 0 {
+		t.Errorf("expected no requests in plaintext, got %d", posts)
+	}
+}
+
+func TestLoadConfigTLS(t *testing.T) {
+	os.Setenv("FACTOMD_TLS_MIN_VERSION", "1.3")
+	os.Setenv("FACTOMD_TLS_PIN", "pin1, pin2")
+	os.Setenv("FACTOM_WALLET_TLS_SERVER_NAME", "walletd.test")
+	defer os.Unsetenv("FACTOMD_TLS_MIN_VERSION")
+	defer os.Unsetenv("FACTOMD_TLS_PIN")
+	defer os.Unsetenv("FACTOM_WALLET_TLS_SERVER_NAME")
+
+	cfg := NewRPCConfig()
+	if err := cfg.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FactomdTLS == nil || cfg.FactomdTLS.MinVersion != tls.VersionTLS13 ||
+		len(cfg.FactomdTLS.PinnedSPKI) != 2 {
+		t.Errorf("factomd TLS settings not loaded: %+v", cfg.FactomdTLS)
+	}
+	if cfg.WalletTLS == nil || cfg.WalletTLS.ServerName != "walletd.test" {
+		t.Errorf("wallet TLS settings not loaded: %+v", cfg.WalletTLS)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// the files of the TLS settings are checked up front
+	cfg.WalletTLS.ClientCertFile = filepath.Join(os.TempDir(), "missing.cert")
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	os.Setenv("FACTOMD_TLS_MIN_VERSION", "1.4")
+	if err := NewRPCConfig().LoadEnv(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unknown TLS version, got %v", err)
+	}
+}
+
+func TestTLSPinCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts, ca, caFile := newTLSServerWithCA(t, dir)
+	defer ts.Close()
+
+	// the CA is only part of the verified chain, the server does not send it
+	c := NewClient(ts.URL)
+	c.TLS = &TLSConfig{CAFiles: []string{caFile}, PinnedSPKI: []string{"sha256/" + SPKIHash(ca)}}
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	// the server certificate can still be pinned
+	c = NewClient(ts.URL)
+	c.TLS = &TLSConfig{CAFiles: []string{caFile}, PinnedSPKI: []string{SPKIHash(ts.Certificate())}}
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
@@ -5,6 +5,7 @@
 package factom
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -156,31 +157,23 @@ func (tx *Transaction) UnmarshalJSON(data []byte) error {
 }
 
 // NewTransaction creates a new temporary Transaction in the wallet.
-func NewTransaction(name string) (*Transaction, error) {
+func (w *WalletClient) NewTransaction(ctx context.Context, name string) (*Transaction, error) {
 	params := transactionRequest{Name: name}
 	req := NewJSON2Request("new-transaction", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
 }
 
 // DeleteTransaction remove a temporary transacton from the wallet.
-func DeleteTransaction(name string) error {
+func (w *WalletClient) DeleteTransaction(ctx context.Context, name string) error {
 	params := transactionRequest{Name: name}
 	req := NewJSON2Request("delete-transaction", APICounter(), params)
 
-	resp, err := walletRequest(req)
+	resp, err := w.walletRequest(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -192,20 +185,12 @@ func DeleteTransaction(name string) error {
 }
 
 // ListTransactionsAll lists all the transactions from the wallet database.
-func ListTransactionsAll() ([]*Transaction, error) {
+func (w *WalletClient) ListTransactionsAll(ctx context.Context) ([]*Transaction, error) {
 	req := NewJSON2Request("transactions", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	list := new(struct {
 		Transactions []*Transaction `json:"transactions"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), list); err != nil {
+	if err := w.walletCall(ctx, req, list); err != nil {
 		return nil, err
 	}
 
@@ -213,7 +198,7 @@ func ListTransactionsAll() ([]*Transaction, error) {
 }
 
 // ListTransactionsAddress lists all transaction to and from a given address.
-func ListTransactionsAddress(addr string) ([]*Transaction, error) {
+func (w *WalletClient) ListTransactionsAddress(ctx context.Context, addr string) ([]*Transaction, error) {
 	params := &struct {
 		Address string `json:"address"`
 	}{
@@ -221,18 +206,10 @@ func ListTransactionsAddress(addr string) ([]*Transaction, error) {
 	}
 
 	req := NewJSON2Request("transactions", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	list := new(struct {
 		Transactions []*Transaction `json:"transactions"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), list); err != nil {
+	if err := w.walletCall(ctx, req, list); err != nil {
 		return nil, err
 	}
 
@@ -241,7 +218,7 @@ func ListTransactionsAddress(addr string) ([]*Transaction, error) {
 
 // ListTransactionsID lists a transaction from the wallet database with a given
 // Transaction ID.
-func ListTransactionsID(id string) ([]*Transaction, error) {
+func (w *WalletClient) ListTransactionsID(ctx context.Context, id string) ([]*Transaction, error) {
 	params := &struct {
 		TxID string `json:"txid"`
 	}{
@@ -249,18 +226,10 @@ func ListTransactionsID(id string) ([]*Transaction, error) {
 	}
 
 	req := NewJSON2Request("transactions", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	list := new(struct {
 		Transactions []*Transaction `json:"transactions"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), list); err != nil {
+	if err := w.walletCall(ctx, req, list); err != nil {
 		return nil, err
 	}
 
@@ -269,7 +238,7 @@ func ListTransactionsID(id string) ([]*Transaction, error) {
 
 // ListTransactionsRange lists all transacions from the wallet database made
 // within a given range of Directory Block heights.
-func ListTransactionsRange(start, end int) ([]*Transaction, error) {
+func (w *WalletClient) ListTransactionsRange(ctx context.Context, start, end int) ([]*Transaction, error) {
 	params := new(struct {
 		Range struct {
 			Start int `json:"start"`
@@ -280,18 +249,10 @@ func ListTransactionsRange(start, end int) ([]*Transaction, error) {
 	params.Range.End = end
 
 	req := NewJSON2Request("transactions", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	list := new(struct {
 		Transactions []*Transaction `json:"transactions"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), list); err != nil {
+	if err := w.walletCall(ctx, req, list); err != nil {
 		return nil, err
 	}
 
@@ -301,20 +262,12 @@ func ListTransactionsRange(start, end int) ([]*Transaction, error) {
 // ListTransactionsTmp lists all of the temporary transaction held in the
 // wallet. Temporary transaction are held by the wallet while they are being
 // constructed and prepaired to be submitted to the network.
-func ListTransactionsTmp() ([]*Transaction, error) {
+func (w *WalletClient) ListTransactionsTmp(ctx context.Context) ([]*Transaction, error) {
 	req := NewJSON2Request("tmp-transactions", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	txs := new(struct {
 		Transactions []*Transaction `json:"transactions"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), txs); err != nil {
+	if err := w.walletCall(ctx, req, txs); err != nil {
 		return nil, err
 	}
 	return txs.Transactions, nil
@@ -323,7 +276,8 @@ func ListTransactionsTmp() ([]*Transaction, error) {
 // AddTransactionInput adds a factoid input to a temporary transaction in the
 // wallet. The imput should come from a Factoid address heald in the wallet
 // database.
-func AddTransactionInput(
+func (w *WalletClient) AddTransactionInput(
+	ctx context.Context,
 	name,
 	address string,
 	amount uint64,
@@ -340,16 +294,8 @@ func AddTransactionInput(
 
 	req := NewJSON2Request("add-input", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -357,7 +303,8 @@ func AddTransactionInput(
 
 // AddTransactionOutput adds a factoid output to a temporary transaction in
 // the wallet.
-func AddTransactionOutput(
+func (w *WalletClient) AddTransactionOutput(
+	ctx context.Context,
 	name,
 	address string,
 	amount uint64,
@@ -374,16 +321,8 @@ func AddTransactionOutput(
 
 	req := NewJSON2Request("add-output", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -391,7 +330,7 @@ func AddTransactionOutput(
 
 // AddTransactionECOutput adds an Entry Credit output to a temporary transaction
 // in the wallet.
-func AddTransactionECOutput(name, address string, amount uint64) (*Transaction, error) {
+func (w *WalletClient) AddTransactionECOutput(ctx context.Context, name, address string, amount uint64) (*Transaction, error) {
 	if AddressStringType(address) != ECPub {
 		return nil, fmt.Errorf("%s is not an Entry Credit address", address)
 	}
@@ -404,16 +343,8 @@ func AddTransactionECOutput(name, address string, amount uint64) (*Transaction,
 
 	req := NewJSON2Request("add-ec-output", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -421,7 +352,7 @@ func AddTransactionECOutput(name, address string, amount uint64) (*Transaction,
 
 // AddTransactionFee adds the appropriate factoid fee payment to a transaction
 // input of a temporary transaction in the wallet.
-func AddTransactionFee(name, address string) (*Transaction, error) {
+func (w *WalletClient) AddTransactionFee(ctx context.Context, name, address string) (*Transaction, error) {
 	if AddressStringType(address) != FactoidPub {
 		return nil, fmt.Errorf("%s is not a Factoid address", address)
 	}
@@ -433,16 +364,8 @@ func AddTransactionFee(name, address string) (*Transaction, error) {
 
 	req := NewJSON2Request("add-fee", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -450,7 +373,7 @@ func AddTransactionFee(name, address string) (*Transaction, error) {
 
 // SubTransactionFee subtracts the appropriate factoid fee payment from a
 // transaction output of a temporary transaction in the wallet.
-func SubTransactionFee(name, address string) (*Transaction, error) {
+func (w *WalletClient) SubTransactionFee(ctx context.Context, name, address string) (*Transaction, error) {
 	params := transactionValueRequest{
 		Name:    name,
 		Address: address,
@@ -458,16 +381,8 @@ func SubTransactionFee(name, address string) (*Transaction, error) {
 
 	req := NewJSON2Request("sub-fee", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -475,7 +390,7 @@ func SubTransactionFee(name, address string) (*Transaction, error) {
 
 // SignTransaction adds the reqired signatures from the appropriate factoid
 // addresses to a temporary transaction in the wallet.
-func SignTransaction(name string, force bool) (*Transaction, error) {
+func (w *WalletClient) SignTransaction(ctx context.Context, name string, force bool) (*Transaction, error) {
 	params := transactionRequest{
 		Name:  name,
 		Force: force,
@@ -483,16 +398,8 @@ func SignTransaction(name string, force bool) (*Transaction, error) {
 
 	req := NewJSON2Request("sign-transaction", APICounter(), params)
 
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	tx := new(Transaction)
-	if err := json.Unmarshal(resp.JSONResult(), tx); err != nil {
+	if err := w.walletCall(ctx, req, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
@@ -505,11 +412,11 @@ func SignTransaction(name string, force bool) (*Transaction, error) {
 // ComposeTransaction may be used by an offline wallet to create an API call
 // that can be securely transfered to an online node to enable transactions from
 // compleatly offline addresses.
-func ComposeTransaction(name string) ([]byte, error) {
+func (w *WalletClient) ComposeTransaction(ctx context.Context, name string) ([]byte, error) {
 	params := transactionRequest{Name: name}
 	req := NewJSON2Request("compose-transaction", APICounter(), params)
 
-	resp, err := walletRequest(req)
+	resp, err := w.walletRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -522,10 +429,10 @@ func ComposeTransaction(name string) ([]byte, error) {
 
 // SendTransaction composes a prepaired temoprary transaction from the wallet
 // and sends it to the factomd API to be included on the factom network.
-func SendTransaction(name string) (*Transaction, error) {
+func (w *WalletClient) SendTransaction(ctx context.Context, name string) (*Transaction, error) {
 	params := transactionRequest{Name: name}
 
-	tx, err := GetTmpTransaction(name)
+	tx, err := w.GetTmpTransaction(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -534,7 +441,7 @@ func SendTransaction(name string) (*Transaction, error) {
 	}
 
 	wreq := NewJSON2Request("compose-transaction", APICounter(), params)
-	wresp, err := walletRequest(wreq)
+	wresp, err := w.walletRequest(ctx, wreq)
 	if err != nil {
 		return nil, err
 	}
@@ -544,14 +451,14 @@ func SendTransaction(name string) (*Transaction, error) {
 
 	freq := new(JSON2Request)
 	json.Unmarshal(wresp.JSONResult(), freq)
-	fresp, err := factomdRequest(freq)
+	fresp, err := w.Factomd.factomdRequest(ctx, freq)
 	if err != nil {
 		return nil, err
 	}
 	if fresp.Error != nil {
 		return nil, fresp.Error
 	}
-	if err := DeleteTransaction(name); err != nil {
+	if err := w.DeleteTransaction(ctx, name); err != nil {
 		return nil, err
 	}
 
@@ -559,38 +466,38 @@ func SendTransaction(name string) (*Transaction, error) {
 }
 
 // SendFactoid creates and sends a transaction to the Factom Network.
-func SendFactoid(from, to string, amount uint64, force bool) (*Transaction, error) {
+func (w *WalletClient) SendFactoid(ctx context.Context, from, to string, amount uint64, force bool) (*Transaction, error) {
 	n := make([]byte, 16)
 	if _, err := rand.Read(n); err != nil {
 		return nil, err
 	}
 	name := hex.EncodeToString(n)
-	if _, err := NewTransaction(name); err != nil {
+	if _, err := w.NewTransaction(ctx, name); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionInput(name, from, amount); err != nil {
+	if _, err := w.AddTransactionInput(ctx, name, from, amount); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionOutput(name, to, amount); err != nil {
+	if _, err := w.AddTransactionOutput(ctx, name, to, amount); err != nil {
 		return nil, err
 	}
-	balance, err := GetFactoidBalance(from)
+	balance, err := w.Factomd.GetFactoidBalance(ctx, from)
 	if err != nil {
 		return nil, err
 	}
 	if balance > int64(amount) {
-		if _, err := AddTransactionFee(name, from); err != nil {
+		if _, err := w.AddTransactionFee(ctx, name, from); err != nil {
 			return nil, err
 		}
 	} else {
-		if _, err := SubTransactionFee(name, to); err != nil {
+		if _, err := w.SubTransactionFee(ctx, name, to); err != nil {
 			return nil, err
 		}
 	}
-	if _, err := SignTransaction(name, force); err != nil {
+	if _, err := w.SignTransaction(ctx, name, force); err != nil {
 		return nil, err
 	}
-	r, err := SendTransaction(name)
+	r, err := w.SendTransaction(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -600,28 +507,28 @@ func SendFactoid(from, to string, amount uint64, force bool) (*Transaction, erro
 
 // BuyEC creates and sends a transaction to the Factom Network that purchases
 // Entry Credits.
-func BuyEC(from, to string, amount uint64, force bool) (*Transaction, error) {
+func (w *WalletClient) BuyEC(ctx context.Context, from, to string, amount uint64, force bool) (*Transaction, error) {
 	n := make([]byte, 16)
 	if _, err := rand.Read(n); err != nil {
 		return nil, err
 	}
 	name := hex.EncodeToString(n)
-	if _, err := NewTransaction(name); err != nil {
+	if _, err := w.NewTransaction(ctx, name); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionInput(name, from, amount); err != nil {
+	if _, err := w.AddTransactionInput(ctx, name, from, amount); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionECOutput(name, to, amount); err != nil {
+	if _, err := w.AddTransactionECOutput(ctx, name, to, amount); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionFee(name, from); err != nil {
+	if _, err := w.AddTransactionFee(ctx, name, from); err != nil {
 		return nil, err
 	}
-	if _, err := SignTransaction(name, force); err != nil {
+	if _, err := w.SignTransaction(ctx, name, force); err != nil {
 		return nil, err
 	}
-	r, err := SendTransaction(name)
+	r, err := w.SendTransaction(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -635,8 +542,8 @@ func BuyEC(from, to string, amount uint64, force bool) (*Transaction, error) {
 // BuyExactEC calculates the and adds the transaction fees and Entry Credit rate
 // so that the exact requested number of Entry Credits are created by the output
 // of the transacton.
-func BuyExactEC(from, to string, amount uint64, force bool) (*Transaction, error) {
-	rate, err := GetECRate()
+func (w *WalletClient) BuyExactEC(ctx context.Context, from, to string, amount uint64, force bool) (*Transaction, error) {
+	rate, err := w.Factomd.GetECRate(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -647,22 +554,22 @@ func BuyExactEC(from, to string, amount uint64, force bool) (*Transaction, error
 	}
 	name := hex.EncodeToString(n)
 
-	if _, err := NewTransaction(name); err != nil {
+	if _, err := w.NewTransaction(ctx, name); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionInput(name, from, amount*rate); err != nil {
+	if _, err := w.AddTransactionInput(ctx, name, from, amount*rate); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionECOutput(name, to, amount*rate); err != nil {
+	if _, err := w.AddTransactionECOutput(ctx, name, to, amount*rate); err != nil {
 		return nil, err
 	}
-	if _, err := AddTransactionFee(name, from); err != nil {
+	if _, err := w.AddTransactionFee(ctx, name, from); err != nil {
 		return nil, err
 	}
-	if _, err := SignTransaction(name, force); err != nil {
+	if _, err := w.SignTransaction(ctx, name, force); err != nil {
 		return nil, err
 	}
-	r, err := SendTransaction(name)
+	r, err := w.SendTransaction(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -673,7 +580,7 @@ func BuyExactEC(from, to string, amount uint64, force bool) (*Transaction, error
 // FactoidSubmit sends a raw transaction to factomd to be included in the
 // network. (See ComposeTransaction for more details on how to build the binary
 // transaction for the network).
-func FactoidSubmit(tx string) (message, txid string, err error) {
+func (c *Client) FactoidSubmit(ctx context.Context, tx string) (message, txid string, err error) {
 	params := &struct {
 		Transaction string
 	}{
@@ -681,19 +588,11 @@ func FactoidSubmit(tx string) (message, txid string, err error) {
 	}
 
 	req := NewJSON2Request("factoid-submit", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return
-	}
-	if resp.Error != nil {
-		return
-	}
-
 	fsr := new(struct {
 		Message string `json:"message"`
 		TxID    string `json:"txid"`
 	})
-	if err = json.Unmarshal(resp.JSONResult(), fsr); err != nil {
+	if err = c.factomdCall(ctx, req, fsr); err != nil {
 		return
 	}
 
@@ -716,19 +615,11 @@ type TransactionResponse struct {
 }
 
 // GetTransaction requests a transaction from the factomd API.
-func GetTransaction(txID string) (*TransactionResponse, error) {
+func (c *Client) GetTransaction(ctx context.Context, txID string) (*TransactionResponse, error) {
 	params := hashRequest{Hash: txID}
 	req := NewJSON2Request("transaction", APICounter(), params)
-	resp, err := factomdRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	txResp := new(TransactionResponse)
-	if err := json.Unmarshal(resp.JSONResult(), txResp); err != nil {
+	if err := c.factomdCall(ctx, req, txResp); err != nil {
 		return nil, err
 	}
 
@@ -736,8 +627,8 @@ func GetTransaction(txID string) (*TransactionResponse, error) {
 }
 
 // GetTmpTransaction requests a temporary transaction from the wallet.
-func GetTmpTransaction(name string) (*Transaction, error) {
-	txs, err := ListTransactionsTmp()
+func (w *WalletClient) GetTmpTransaction(ctx context.Context, name string) (*Transaction, error) {
+	txs, err := w.ListTransactionsTmp(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,7 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 )
 
 // PendingTransaction is a single transaction returned by the pending-transaction API call
@@ -18,21 +18,10 @@ type PendingTransaction struct {
 // GetPendingTransactions requests a list of transactions that have been
 // submitted to the Factom Network, but have not yet been included in a Factoid
 // Block.
-func GetPendingTransactions() ([]PendingTransaction, error) {
+func (c *Client) GetPendingTransactions(ctx context.Context) ([]PendingTransaction, error) {
 	req := NewJSON2Request("pending-transactions", APICounter(), nil)
-	resp, err := factomdRequest(req)
-
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
-	transList := resp.JSONResult()
-
 	var res []PendingTransaction
-	if err := json.Unmarshal(transList, &res); err != nil {
+	if err := c.factomdCall(ctx, req, &res); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,220 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TransportConfig holds the settings of the http.Transport used to connect to
+// an API server. The zero value uses the settings of http.DefaultTransport.
+type TransportConfig struct {
+	// TLSEnable, TLSCertFile and TLS are set from the settings of the Client
+	// or WalletClient.
+	TLSEnable   bool
+	TLSCertFile string
+	TLS         *TLSConfig
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// maxTransports is the number of shared transports that are kept. The least
+// recently used transport is dropped to make room for a new one.
+const maxTransports = 16
+
+var (
+	// transports holds one shared http.Transport per transportKey so that
+	// the clients with the same settings share their idle connections.
+	transports     = make(map[transportKey]*cachedTransport)
+	transportsUsed uint64
+	transportsLock sync.Mutex
+)
+
+// cachedTransport is a shared http.Transport and the time it was last used,
+// counted in uses of the shared transports.
+type cachedTransport struct {
+	tr   *http.Transport
+	used uint64
+}
+
+// transportKey identifies a TransportConfig by the value of its settings, so
+// that a new TLSConfig naming the same files, such as one from a reloaded
+// configuration, reuses the transport built for the old one.
+type transportKey struct {
+	TLSEnable   bool
+	TLSCertFile string
+
+	TLS            bool
+	CAFiles        string
+	NoSystemRoots  bool
+	ClientCertFile string
+	ClientKeyFile  string
+	ServerName     string
+	MinVersion     uint16
+	PinnedSPKI     string
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+func (cfg TransportConfig) key() transportKey {
+	k := transportKey{
+		TLSEnable:           cfg.TLSEnable,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout,
+	}
+	if cfg.TLSEnable {
+		k.TLSCertFile = cfg.TLSCertFile
+	}
+	if t := cfg.TLS; t != nil {
+		k.TLS = true
+		k.CAFiles = strings.Join(t.CAFiles, "\n")
+		k.NoSystemRoots = t.NoSystemRoots
+		k.ClientCertFile = t.ClientCertFile
+		k.ClientKeyFile = t.ClientKeyFile
+		k.ServerName = t.ServerName
+		k.MinVersion = t.MinVersion
+		k.PinnedSPKI = strings.Join(t.PinnedSPKI, "\n")
+	}
+	return k
+}
+
+// ResetTransports drops the shared transports of the clients, closing their
+// idle connections. The next requests build new transports, reading the TLS
+// certificate files again, so this picks up a certificate or key that was
+// replaced at the same path.
+func ResetTransports() {
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+
+	for key, t := range transports {
+		t.tr.CloseIdleConnections()
+		delete(transports, key)
+	}
+}
+
+// sharedTransport returns the http.Transport for the configuration, building
+// it on the first use. The TLS certificate files are only read when the
+// transport is built.
+func sharedTransport(cfg TransportConfig) (*http.Transport, error) {
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+
+	transportsUsed++
+	key := cfg.key()
+	if t, ok := transports[key]; ok {
+		t.used = transportsUsed
+		return t.tr, nil
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		var certFile string
+		if cfg.TLSEnable {
+			certFile = cfg.TLSCertFile
+		}
+		c, err := cfg.TLS.tlsConfig(certFile)
+		if err != nil {
+			return nil, err
+		}
+		tr.TLSClientConfig = c
+	} else if cfg.TLSEnable {
+		caCert, err := ioutil.ReadFile(cfg.TLSCertFile)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSCertFile)
+		}
+		tr.TLSClientConfig = &tls.Config{RootCAs: caCertPool}
+	}
+	if cfg.MaxIdleConns > 0 {
+		tr.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	if len(transports) >= maxTransports {
+		dropOldestTransport()
+	}
+	transports[key] = &cachedTransport{tr: tr, used: transportsUsed}
+	return tr, nil
+}
+
+// dropOldestTransport drops the least recently used shared transport and
+// closes its idle connections. The caller must hold transportsLock.
+func dropOldestTransport() {
+	var oldest transportKey
+	var used uint64
+	for key, t := range transports {
+		if used == 0 || t.used < used {
+			oldest, used = key, t.used
+		}
+	}
+	transports[oldest].tr.CloseIdleConnections()
+	delete(transports, oldest)
+}
+
+// httpClient returns the http.Client used to send the requests of the Client.
+func (c *Client) httpClient() (*http.Client, error) {
+	if c.HTTPClient != nil {
+		return c.HTTPClient, nil
+	}
+
+	tr := c.Transport
+	if tr == nil {
+		cfg := c.TransportConfig
+		cfg.TLSEnable = c.TLSEnable
+		cfg.TLSCertFile = c.TLSCertFile
+		cfg.TLS = c.TLS
+		t, err := sharedTransport(cfg)
+		if err != nil {
+			return nil, err
+		}
+		tr = t
+	}
+
+	// no effect if Jar is nil
+	return &http.Client{Transport: tr, Timeout: c.Timeout, Jar: c.Jar}, nil
+}
+
+// httpClient returns the http.Client used to send the requests of the
+// WalletClient.
+func (w *WalletClient) httpClient() (*http.Client, error) {
+	if w.HTTPClient != nil {
+		return w.HTTPClient, nil
+	}
+
+	tr := w.Transport
+	if tr == nil {
+		cfg := w.TransportConfig
+		cfg.TLSEnable = w.TLSEnable
+		cfg.TLSCertFile = w.TLSCertFile
+		cfg.TLS = w.TLS
+		t, err := sharedTransport(cfg)
+		if err != nil {
+			return nil, err
+		}
+		tr = t
+	}
+
+	return &http.Client{Transport: tr, Timeout: w.Timeout}, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2016 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/FactomProject/factom"
+
+	"testing"
+)
+
+func rateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"rate":1000}}`)
+}
+
+func TestTransportReusesConnections(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(rateHandler))
+	ts.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	SetFactomdServer(ts.URL[7:])
+	c := NewClient(ts.URL)
+	for i := 0; i < 10; i++ {
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		// the package level functions share the transport too
+		if _, err := GetECRate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("expected 1 connection, got %d", n)
+	}
+}
+
+func TestTransportTLSCertReadOnce(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(rateHandler))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "factomdAPIpub.cert")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := ioutil.WriteFile(certFile, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(ts.URL[8:])
+	c.TLSEnable = true
+	c.TLSCertFile = certFile
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the certificate is not read again once the transport is built
+	os.Remove(certFile)
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResetTransports(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(rateHandler))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile := writeServerCert(t, dir, ts)
+
+	c := NewClient(ts.URL)
+	c.TLS = &TLSConfig{CAFiles: []string{caFile}}
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// a certificate replaced at the same path is read after the reset
+	if err := ioutil.WriteFile(caFile, []byte("rotated"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Error(err)
+	}
+	ResetTransports()
+	if _, err := c.GetECRate(context.Background()); err == nil {
+		t.Error("expected an error after the certificate was replaced")
+	}
+}
+
+func TestTransportEviction(t *testing.T) {
+	var conns, closed int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(rateHandler))
+	ts.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		switch s {
+		case http.StateNew:
+			atomic.AddInt32(&conns, 1)
+		case http.StateClosed:
+			atomic.AddInt32(&closed, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	// more distinct settings than there are shared transports kept
+	for i := 1; i <= 32; i++ {
+		c := NewClient(ts.URL)
+		c.TransportConfig.MaxIdleConns = i
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first transport was dropped, so its settings need a new connection
+	c := NewClient(ts.URL)
+	c.TransportConfig.MaxIdleConns = 1
+	if _, err := c.GetECRate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&conns); n != 33 {
+		t.Errorf("expected 33 connections, got %d", n)
+	}
+
+	// and the idle connections of the dropped transports are closed
+	for i := 0; i < 100 && atomic.LoadInt32(&closed) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&closed) == 0 {
+		t.Error("expected the idle connections of the dropped transports to be closed")
+	}
+}
+
+func TestTransportSharedByTLSSettings(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(rateHandler))
+	ts.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "factom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile := writeServerCert(t, dir, ts)
+
+	// every client has its own TLSConfig, as after reloading a configuration
+	for i := 0; i < 5; i++ {
+		c := NewClient(ts.URL)
+		c.TLS = &TLSConfig{CAFiles: []string{caFile}}
+		if _, err := c.GetECRate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("expected 1 connection, got %d", n)
+	}
+}
+
+type countingTransport struct {
+	requests int32
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestTransportInjection(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(rateHandler))
+	defer ts.Close()
+
+	tr := new(countingTransport)
+	c := NewClient(ts.URL)
+	c.Transport = tr
+	c.GetECRate(context.Background())
+
+	c = NewClient(ts.URL)
+	c.HTTPClient = &http.Client{Transport: tr}
+	c.GetECRate(context.Background())
+
+	w := NewWalletClient(ts.URL[7:], nil)
+	w.Transport = tr
+	w.SendRequest(context.Background(), NewJSON2Request("properties", 0, nil))
+
+	if tr.requests != 3 {
+		t.Errorf("expected 3 requests through the transport, got %d", tr.requests)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -27,10 +28,15 @@ const (
 var (
 	// RpcConfig sets the default target for the factomd and walletd API servers
 	RpcConfig = &RPCConfig{
-		FactomdServer: "localhost:8088",
-		WalletServer:  "localhost:8089",
+		FactomdServer:          "localhost:8088",
+		WalletServer:           "localhost:8089",
+		FactomdMaxResponseSize: DefaultMaxResponseSize,
+		WalletMaxResponseSize:  DefaultMaxResponseSize,
 	}
 	cookieJar http.CookieJar
+
+	// rpcConfigLock guards RpcConfig and cookieJar
+	rpcConfigLock sync.RWMutex
 )
 
 // EntryCost calculates the cost in Entry Credits of adding an Entry to a Chain
@@ -46,8 +52,8 @@ func EntryCost(e *Entry) (int8, error) {
 	// caulculate the length exluding the header size 35 for Milestone 1
 	l := len(p) - 35
 
-	if l > 10240 {
-		return 10, fmt.Errorf("Entry cannot be larger than 10KB")
+	if l > MaxEntryPayloadSize {
+		return 10, ErrEntryTooLarge
 	}
 
 	// n is the capacity of the entry payment in KB
@@ -115,6 +121,18 @@ func milliTime() (r []byte) {
 	return buf.Bytes()[2:]
 }
 
+// sha Sha256 Hash; sha256(data)
+func sha(data []byte) []byte {
+	h := sha256.Sum256(data)
+	return h[:]
+}
+
+// sha512Half Sha512 Half Hash; the first 32 bytes of sha512(data)
+func sha512Half(data []byte) []byte {
+	h := sha512.Sum512(data)
+	return h[:32]
+}
+
 // shad Double Sha256 Hash; sha256(sha256(data))
 func shad(data []byte) []byte {
 	h1 := sha256.Sum256(data)
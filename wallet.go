@@ -5,39 +5,31 @@
 package factom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
 // BackupWallet returns a formatted string with the wallet seed and the secret
 // keys for all of the wallet addresses.
-func BackupWallet() (string, error) {
+func (w *WalletClient) BackupWallet(ctx context.Context) (string, error) {
 	req := NewJSON2Request("wallet-backup", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != nil {
-		return "", resp.Error
-	}
-
-	w := new(struct {
+	b := new(struct {
 		Seed         string             `json:"wallet-seed"`
 		Addresses    []*addressResponse `json:"addresses"`
 		IdentityKeys []*addressResponse `json:"identity-keys"`
 	})
-	if err := json.Unmarshal(resp.JSONResult(), w); err != nil {
+	if err := w.walletCall(ctx, req, b); err != nil {
 		return "", err
 	}
 
-	s := fmt.Sprintln(w.Seed)
+	s := fmt.Sprintln(b.Seed)
 	s += fmt.Sprintln()
-	for _, adr := range w.Addresses {
+	for _, adr := range b.Addresses {
 		s += fmt.Sprintln(adr.Public)
 		s += fmt.Sprintln(adr.Secret)
 		s += fmt.Sprintln()
 	}
-	for _, k := range w.IdentityKeys {
+	for _, k := range b.IdentityKeys {
 		s += fmt.Sprintln(k.Public)
 		s += fmt.Sprintln(k.Secret)
 		s += fmt.Sprintln()
@@ -47,18 +39,10 @@ func BackupWallet() (string, error) {
 
 // GenerateFactoidAddress creates a new Factoid Address and stores it in the
 // Factom Wallet.
-func GenerateFactoidAddress() (*FactoidAddress, error) {
+func (w *WalletClient) GenerateFactoidAddress(ctx context.Context) (*FactoidAddress, error) {
 	req := NewJSON2Request("generate-factoid-address", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	a := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), a); err != nil {
+	if err := w.walletCall(ctx, req, a); err != nil {
 		return nil, err
 	}
 	f, err := GetFactoidAddress(a.Secret)
@@ -71,18 +55,10 @@ func GenerateFactoidAddress() (*FactoidAddress, error) {
 
 // GenerateECAddress creates a new Entry Credit Address and stores it in the
 // Factom Wallet.
-func GenerateECAddress() (*ECAddress, error) {
+func (w *WalletClient) GenerateECAddress(ctx context.Context) (*ECAddress, error) {
 	req := NewJSON2Request("generate-ec-address", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	a := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), a); err != nil {
+	if err := w.walletCall(ctx, req, a); err != nil {
 		return nil, err
 	}
 	e, err := GetECAddress(a.Secret)
@@ -93,18 +69,10 @@ func GenerateECAddress() (*ECAddress, error) {
 	return e, nil
 }
 
-func GenerateIdentityKey() (*IdentityKey, error) {
+func (w *WalletClient) GenerateIdentityKey(ctx context.Context) (*IdentityKey, error) {
 	req := NewJSON2Request("generate-identity-key", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	k := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), k); err != nil {
+	if err := w.walletCall(ctx, req, k); err != nil {
 		return nil, err
 	}
 	e, err := GetIdentityKey(k.Secret)
@@ -117,7 +85,7 @@ func GenerateIdentityKey() (*IdentityKey, error) {
 
 // ImportAddresses takes a number of Factoid and Entry Creidit secure keys and
 // stores the Facotid and Entry Credit addresses in the Factom Wallet.
-func ImportAddresses(addrs ...string) (
+func (w *WalletClient) ImportAddresses(ctx context.Context, addrs ...string) (
 	[]*FactoidAddress,
 	[]*ECAddress,
 	error) {
@@ -128,16 +96,8 @@ func ImportAddresses(addrs ...string) (
 		params.Addresses = append(params.Addresses, s)
 	}
 	req := NewJSON2Request("import-addresses", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.Error != nil {
-		return nil, nil, resp.Error
-	}
-
 	r := new(multiAddressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, nil, err
 	}
 	fs := make([]*FactoidAddress, 0)
@@ -170,7 +130,7 @@ func ImportAddresses(addrs ...string) (
 // This functionality is used only to recover addresses that were funded by the
 // Factom Genisis block to pay participants in the initial Factom network crowd
 // funding.
-func ImportKoinify(mnemonic string) (*FactoidAddress, error) {
+func (w *WalletClient) ImportKoinify(ctx context.Context, mnemonic string) (*FactoidAddress, error) {
 	params := &struct {
 		Words string `json:"words"`
 	}{
@@ -178,16 +138,8 @@ func ImportKoinify(mnemonic string) (*FactoidAddress, error) {
 	}
 
 	req := NewJSON2Request("import-koinify", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	r := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, err
 	}
 	f, err := GetFactoidAddress(r.Secret)
@@ -200,12 +152,12 @@ func ImportKoinify(mnemonic string) (*FactoidAddress, error) {
 
 // RemoveAddress removes an address from the Factom Wallet database.
 // (Be careful!)
-func RemoveAddress(address string) error {
+func (w *WalletClient) RemoveAddress(ctx context.Context, address string) error {
 	params := new(addressRequest)
 	params.Address = address
 
 	req := NewJSON2Request("remove-address", APICounter(), params)
-	resp, err := walletRequest(req)
+	resp, err := w.walletRequest(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -217,21 +169,13 @@ func RemoveAddress(address string) error {
 }
 
 // FetchAddresses requests all of the addresses in the Factom Wallet database.
-func FetchAddresses() ([]*FactoidAddress, []*ECAddress, error) {
+func (w *WalletClient) FetchAddresses(ctx context.Context) ([]*FactoidAddress, []*ECAddress, error) {
 	req := NewJSON2Request("all-addresses", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.Error != nil {
-		return nil, nil, resp.Error
-	}
-
 	fs := make([]*FactoidAddress, 0)
 	es := make([]*ECAddress, 0)
 
 	as := new(multiAddressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), as); err != nil {
+	if err := w.walletCall(ctx, req, as); err != nil {
 		return nil, nil, err
 	}
 
@@ -258,7 +202,7 @@ func FetchAddresses() ([]*FactoidAddress, []*ECAddress, error) {
 }
 
 // FetchECAddress requests an Entry Credit address from the Factom Wallet.
-func FetchECAddress(ecpub string) (*ECAddress, error) {
+func (w *WalletClient) FetchECAddress(ctx context.Context, ecpub string) (*ECAddress, error) {
 	if AddressStringType(ecpub) != ECPub {
 		return nil, fmt.Errorf(
 			"%s is not an Entry Credit Public Address", ecpub)
@@ -267,16 +211,8 @@ func FetchECAddress(ecpub string) (*ECAddress, error) {
 	params.Address = ecpub
 
 	req := NewJSON2Request("address", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	r := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, err
 	}
 
@@ -284,7 +220,7 @@ func FetchECAddress(ecpub string) (*ECAddress, error) {
 }
 
 // FetchFactoidAddress requests a Factom address from the Factom Wallet.
-func FetchFactoidAddress(fctpub string) (*FactoidAddress, error) {
+func (w *WalletClient) FetchFactoidAddress(ctx context.Context, fctpub string) (*FactoidAddress, error) {
 	if AddressStringType(fctpub) != FactoidPub {
 		return nil, fmt.Errorf("%s is not a Factoid Address", fctpub)
 	}
@@ -292,23 +228,15 @@ func FetchFactoidAddress(fctpub string) (*FactoidAddress, error) {
 	params.Address = fctpub
 
 	req := NewJSON2Request("address", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	r := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, err
 	}
 
 	return GetFactoidAddress(r.Secret)
 }
 
-func ImportIdentityKeys(pubs ...string) ([]*IdentityKey, error) {
+func (w *WalletClient) ImportIdentityKeys(ctx context.Context, pubs ...string) ([]*IdentityKey, error) {
 	params := new(struct {
 		IdentityKeys []secretRequest `json:"keys"`
 	})
@@ -318,16 +246,8 @@ func ImportIdentityKeys(pubs ...string) ([]*IdentityKey, error) {
 	}
 
 	req := NewJSON2Request("import-identity-keys", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	r := new(multiIdentityKeyResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, err
 	}
 	keys := make([]*IdentityKey, 0)
@@ -342,43 +262,27 @@ func ImportIdentityKeys(pubs ...string) ([]*IdentityKey, error) {
 	return keys, nil
 }
 
-func FetchIdentityKey(pub string) (*IdentityKey, error) {
+func (w *WalletClient) FetchIdentityKey(ctx context.Context, pub string) (*IdentityKey, error) {
 	params := new(struct {
 		Public string `json:"public"`
 	})
 	params.Public = pub
 
 	req := NewJSON2Request("identity-key", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	r := new(addressResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, err
 	}
 
 	return GetIdentityKey(r.Secret)
 }
 
-func FetchIdentityKeys() ([]*IdentityKey, error) {
+func (w *WalletClient) FetchIdentityKeys(ctx context.Context) ([]*IdentityKey, error) {
 	req := NewJSON2Request("all-identity-keys", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
 	keys := make([]*IdentityKey, 0)
 
 	multiKeyResp := new(multiIdentityKeyResponse)
-	if err := json.Unmarshal(resp.JSONResult(), multiKeyResp); err != nil {
+	if err := w.walletCall(ctx, req, multiKeyResp); err != nil {
 		return nil, err
 	}
 
@@ -397,14 +301,14 @@ func FetchIdentityKeys() ([]*IdentityKey, error) {
 	return keys, nil
 }
 
-func RemoveIdentityKey(pub string) error {
+func (w *WalletClient) RemoveIdentityKey(ctx context.Context, pub string) error {
 	params := new(struct {
 		Public string `json:"public"`
 	})
 	params.Public = pub
 
 	req := NewJSON2Request("remove-identity-key", APICounter(), params)
-	resp, err := walletRequest(req)
+	resp, err := w.walletRequest(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -417,36 +321,20 @@ func RemoveIdentityKey(pub string) error {
 
 // GetWalletHeight requests the current block heights known to the Factom
 // Wallet.
-func GetWalletHeight() (uint32, error) {
+func (w *WalletClient) GetWalletHeight(ctx context.Context) (uint32, error) {
 	req := NewJSON2Request("get-height", APICounter(), nil)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return 0, err
-	}
-	if resp.Error != nil {
-		return 0, resp.Error
-	}
-
 	r := new(heightResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return 0, err
 	}
 
 	return uint32(r.Height), nil
 }
 
-func UnlockWallet(passphrase string, seconds int64) (int64, error) {
+func (w *WalletClient) UnlockWallet(ctx context.Context, passphrase string, seconds int64) (int64, error) {
 	req := NewJSON2Request("unlock-wallet", APICounter(), &passphraseRequest{Password: passphrase, Timeout: seconds})
-	resp, err := walletRequest(req)
-	if err != nil {
-		return 0, err
-	}
-	if resp.Error != nil {
-		return 0, resp.Error
-	}
-
 	r := new(unlockResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return 0, err
 	}
 
@@ -490,23 +378,15 @@ type composeEntryResponse struct {
 // WalletComposeChainCommitReveal may be used by an offline wallet to create the
 // calls needed to create new chains while keeping addresses secure in an
 // offline wallet.
-func WalletComposeChainCommitReveal(chain *Chain, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+func (w *WalletClient) WalletComposeChainCommitReveal(ctx context.Context, chain *Chain, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
 	params := new(composeChainRequest)
 	params.Chain = *chain
 	params.ECPub = ecPub
 	params.Force = force
 
 	req := NewJSON2Request("compose-chain", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
-		return nil, nil, err
-	}
-	if resp.Error != nil {
-		return nil, nil, resp.Error
-	}
-
 	r := new(composeEntryResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, nil, err
 	}
 
@@ -520,29 +400,211 @@ func WalletComposeChainCommitReveal(chain *Chain, ecPub string, force bool) (*JS
 // WalletComposeEntryCommitReveal may be used by an offline wallet to create the
 // calls needed to create new entries while keeping addresses secure in an
 // offline wallet.
-func WalletComposeEntryCommitReveal(entry *Entry, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
+func (w *WalletClient) WalletComposeEntryCommitReveal(ctx context.Context, entry *Entry, ecPub string, force bool) (*JSON2Request, *JSON2Request, error) {
 	params := new(composeEntryRequest)
 	params.Entry = *entry
 	params.ECPub = ecPub
 	params.Force = force
 
 	req := NewJSON2Request("compose-entry", APICounter(), params)
-	resp, err := walletRequest(req)
-	if err != nil {
+	r := new(composeEntryResponse)
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, nil, err
 	}
-	if resp.Error != nil {
-		return nil, nil, resp.Error
+
+	return r.Commit, r.Reveal, nil
+}
+
+// WalletComposeIdentityChainCommitReveal composes commit and reveal json
+// objects that may be used to make API calls to the factomd API to create a
+// new identity Chain with the given name and initial public keys, as
+// NewIdentityChain does.
+func (w *WalletClient) WalletComposeIdentityChainCommitReveal(
+	ctx context.Context,
+	name []string,
+	pubKeys []string,
+	ecPub string,
+	force bool,
+) (*JSON2Request, *JSON2Request, error) {
+	params := struct {
+		Name    []string `json:"name"`
+		PubKeys []string `json:"pubkeys"`
+		ECPub   string   `json:"ecpub"`
+		Force   bool     `json:"force"`
+	}{
+		Name:    name,
+		PubKeys: pubKeys,
+		ECPub:   ecPub,
+		Force:   force,
+	}
+
+	req := NewJSON2Request("compose-identity-chain", APICounter(), params)
+	r := new(composeEntryResponse)
+	if err := w.walletCall(ctx, req, r); err != nil {
+		return nil, nil, err
+	}
+
+	return r.Commit, r.Reveal, nil
+}
+
+// WalletComposeIdentityKeyReplacementCommitReveal composes commit and reveal
+// json objects that may be used to make API calls to the factomd API to
+// replace oldKey with newKey in an identity, as NewIdentityKeyReplacementEntry
+// does. The entry is signed by the wallet with the identity key of
+// signerPubKey, so the secret key never leaves the wallet.
+func (w *WalletClient) WalletComposeIdentityKeyReplacementCommitReveal(
+	ctx context.Context,
+	chainID string,
+	oldKey string,
+	newKey string,
+	signerPubKey string,
+	ecPub string,
+	force bool,
+) (*JSON2Request, *JSON2Request, error) {
+	params := struct {
+		ChainID   string `json:"chainid"`
+		OldKey    string `json:"oldkey"`
+		NewKey    string `json:"newkey"`
+		SignerKey string `json:"signerkey"`
+		ECPub     string `json:"ecpub"`
+		Force     bool   `json:"force"`
+	}{
+		ChainID:   chainID,
+		OldKey:    oldKey,
+		NewKey:    newKey,
+		SignerKey: signerPubKey,
+		ECPub:     ecPub,
+		Force:     force,
+	}
+
+	req := NewJSON2Request("compose-identity-key-replacement", APICounter(), params)
+	r := new(composeEntryResponse)
+	if err := w.walletCall(ctx, req, r); err != nil {
+		return nil, nil, err
+	}
+
+	return r.Commit, r.Reveal, nil
+}
+
+// WalletComposeIdentityAttributeCommitReveal composes commit and reveal json
+// objects that may be used to make API calls to the factomd API to assign
+// attributes to the receiver identity, as NewIdentityAttributeEntry does. The
+// entry is signed by the wallet with the identity key of signerPubKey.
+func (w *WalletClient) WalletComposeIdentityAttributeCommitReveal(
+	ctx context.Context,
+	receiverChainID string,
+	destinationChainID string,
+	attributes []IdentityAttribute,
+	signerPubKey string,
+	signerChainID string,
+	ecPub string,
+	force bool,
+) (*JSON2Request, *JSON2Request, error) {
+	params := struct {
+		ReceiverChainID    string              `json:"receiver-chainid"`
+		DestinationChainID string              `json:"destination-chainid"`
+		Attributes         []IdentityAttribute `json:"attributes"`
+		SignerKey          string              `json:"signerkey"`
+		SignerChainID      string              `json:"signer-chainid"`
+		ECPub              string              `json:"ecpub"`
+		Force              bool                `json:"force"`
+	}{
+		ReceiverChainID:    receiverChainID,
+		DestinationChainID: destinationChainID,
+		Attributes:         attributes,
+		SignerKey:          signerPubKey,
+		SignerChainID:      signerChainID,
+		ECPub:              ecPub,
+		Force:              force,
 	}
 
+	req := NewJSON2Request("compose-identity-attribute", APICounter(), params)
 	r := new(composeEntryResponse)
-	if err := json.Unmarshal(resp.JSONResult(), r); err != nil {
+	if err := w.walletCall(ctx, req, r); err != nil {
 		return nil, nil, err
 	}
 
 	return r.Commit, r.Reveal, nil
 }
 
+// WalletComposeIdentityAttributeEndorsementCommitReveal composes commit and
+// reveal json objects that may be used to make API calls to the factomd API
+// to endorse the attribute entry with the given hash, as
+// NewIdentityAttributeEndorsementEntry does. The entry is signed by the wallet
+// with the identity key of signerPubKey.
+func (w *WalletClient) WalletComposeIdentityAttributeEndorsementCommitReveal(
+	ctx context.Context,
+	destinationChainID string,
+	entryHash string,
+	signerPubKey string,
+	signerChainID string,
+	ecPub string,
+	force bool,
+) (*JSON2Request, *JSON2Request, error) {
+	params := struct {
+		DestinationChainID string `json:"destination-chainid"`
+		EntryHash          string `json:"entry-hash"`
+		SignerKey          string `json:"signerkey"`
+		SignerChainID      string `json:"signer-chainid"`
+		ECPub              string `json:"ecpub"`
+		Force              bool   `json:"force"`
+	}{
+		DestinationChainID: destinationChainID,
+		EntryHash:          entryHash,
+		SignerKey:          signerPubKey,
+		SignerChainID:      signerChainID,
+		ECPub:              ecPub,
+		Force:              force,
+	}
+
+	req := NewJSON2Request("compose-identity-attribute-endorsement", APICounter(), params)
+	r := new(composeEntryResponse)
+	if err := w.walletCall(ctx, req, r); err != nil {
+		return nil, nil, err
+	}
+
+	return r.Commit, r.Reveal, nil
+}
+
+type activeIdentityKeysResponse struct {
+	ChainID string   `json:"chainid"`
+	Height  int64    `json:"height"`
+	Keys    []string `json:"keys"`
+}
+
+// FetchActiveIdentityKeys requests from the wallet the identity's public keys
+// that are active at the highest saved block height, along with that height.
+func (w *WalletClient) FetchActiveIdentityKeys(ctx context.Context, chainID string) ([]string, int64, error) {
+	params := struct {
+		ChainID string `json:"chainid"`
+	}{ChainID: chainID}
+
+	req := NewJSON2Request("active-identity-keys", APICounter(), params)
+	r := new(activeIdentityKeysResponse)
+	if err := w.walletCall(ctx, req, r); err != nil {
+		return nil, -1, err
+	}
+
+	return r.Keys, r.Height, nil
+}
+
+// FetchActiveIdentityKeysAtHeight requests from the wallet the identity's
+// public keys that were active at the specified block height.
+func (w *WalletClient) FetchActiveIdentityKeysAtHeight(ctx context.Context, chainID string, height int64) ([]string, error) {
+	params := struct {
+		ChainID string `json:"chainid"`
+		Height  int64  `json:"height"`
+	}{ChainID: chainID, Height: height}
+
+	req := NewJSON2Request("active-identity-keys", APICounter(), params)
+	r := new(activeIdentityKeysResponse)
+	if err := w.walletCall(ctx, req, r); err != nil {
+		return nil, err
+	}
+
+	return r.Keys, nil
+}
+
 type heightResponse struct {
 	Height int64 `json:"height"`
 }
@@ -6,10 +6,12 @@ package factom_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 
 	. "github.com/FactomProject/factom"
 
@@ -122,3 +124,94 @@ func populateTestWallet() error {
 
 	return nil
 }
+
+func TestWalletComposeIdentity(t *testing.T) {
+	params := make(map[string]map[string]interface{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		})
+		json.NewDecoder(r.Body).Decode(req)
+		params[req.Method] = req.Params
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "active-identity-keys" {
+			fmt.Fprintln(w, `{"jsonrpc":"2.0","id":0,"result":{"chainid":"3b69dabe22c014af9a9bc9dfa7917ce4602a03579597ddf184d8de56702512ae","height":20000,"keys":["idpub2k8zGYQUfekxehyUKeqPw6QPiJ5hkV3bbc9JBgL7GNrEiqMpQX","idpub2Mq5Q1ZBVxAQvkHj3nqZFXoWB8xg3iVVsiLbnCU5zKVRfyMs6H"]}}`)
+			return
+		}
+		kind := "entry"
+		if req.Method == "compose-identity-chain" {
+			kind = "chain"
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":0,"result":{"commit":{"jsonrpc":"2.0","id":0,"params":{"message":"00"},"method":"commit-%s"},"reveal":{"jsonrpc":"2.0","id":0,"params":{"entry":"00"},"method":"reveal-%s"}}}`+"\n",
+			kind, kind)
+	}))
+	defer ts.Close()
+
+	w := NewWalletClient(ts.URL, nil)
+	ctx := context.Background()
+	ecPub := "EC2DKSYyRcNWf7RS963VFYgMExoHRYLHVeCfQ9PGPmNzwrcmgm2r"
+	chainID := "3b69dabe22c014af9a9bc9dfa7917ce4602a03579597ddf184d8de56702512ae"
+	signer := "idpub2k8zGYQUfekxehyUKeqPw6QPiJ5hkV3bbc9JBgL7GNrEiqMpQX"
+
+	commit, reveal, err := w.WalletComposeIdentityChainCommitReveal(ctx,
+		[]string{"Factom", "Test"}, []string{signer}, ecPub, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Method != "commit-chain" || reveal.Method != "reveal-chain" {
+		t.Errorf("expected commit-chain and reveal-chain, got %s and %s", commit.Method, reveal.Method)
+	}
+	if p := params["compose-identity-chain"]; p["ecpub"] != ecPub || len(p["name"].([]interface{})) != 2 {
+		t.Errorf("unexpected compose-identity-chain params %v", p)
+	}
+
+	commit, reveal, err = w.WalletComposeIdentityKeyReplacementCommitReveal(ctx,
+		chainID, signer, "idpub2Mq5Q1ZBVxAQvkHj3nqZFXoWB8xg3iVVsiLbnCU5zKVRfyMs6H", signer, ecPub, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Method != "commit-entry" || reveal.Method != "reveal-entry" {
+		t.Errorf("expected commit-entry and reveal-entry, got %s and %s", commit.Method, reveal.Method)
+	}
+	if p := params["compose-identity-key-replacement"]; p["chainid"] != chainID || p["signerkey"] != signer || p["force"] != true {
+		t.Errorf("unexpected compose-identity-key-replacement params %v", p)
+	}
+
+	attrs := []IdentityAttribute{{Key: "email", Value: "abc@def.ghi"}}
+	if _, _, err := w.WalletComposeIdentityAttributeCommitReveal(ctx,
+		chainID, chainID, attrs, signer, chainID, ecPub, false); err != nil {
+		t.Fatal(err)
+	}
+	if p := params["compose-identity-attribute"]; p["receiver-chainid"] != chainID || p["signer-chainid"] != chainID ||
+		len(p["attributes"].([]interface{})) != 1 {
+		t.Errorf("unexpected compose-identity-attribute params %v", p)
+	}
+
+	entryHash := "c07f1d89bb6c43e7e3166b9e53672110ff8077c367758fbe4265561c8b91e675"
+	if _, _, err := w.WalletComposeIdentityAttributeEndorsementCommitReveal(ctx,
+		chainID, entryHash, signer, chainID, ecPub, false); err != nil {
+		t.Fatal(err)
+	}
+	if p := params["compose-identity-attribute-endorsement"]; p["entry-hash"] != entryHash || p["destination-chainid"] != chainID {
+		t.Errorf("unexpected compose-identity-attribute-endorsement params %v", p)
+	}
+
+	keys, height, err := w.FetchActiveIdentityKeys(ctx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || height != 20000 {
+		t.Errorf("unexpected active identity keys %v at height %d", keys, height)
+	}
+	if _, ok := params["active-identity-keys"]["height"]; ok {
+		t.Error("expected no height for the latest active identity keys")
+	}
+	if _, err := w.FetchActiveIdentityKeysAtHeight(ctx, chainID, 10000); err != nil {
+		t.Fatal(err)
+	}
+	if h := params["active-identity-keys"]["height"]; h != float64(10000) {
+		t.Errorf("expected a height of 10000, got %v", h)
+	}
+}